@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	lib "lem-in/helpers"
+)
+
+// runAnalyze — "lem-in analyze <what> map.txt": анализ карты без решения
+func runAnalyze(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: lem-in analyze cut map.txt")
+		fmt.Println("       lem-in analyze links [-add a-b,...] [-within d] [-k n] [-solver name] map.txt")
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "cut":
+		runAnalyzeCut(args[1:])
+	case "links":
+		runAnalyzeLinks(args[1:])
+	default:
+		fmt.Printf("Unknown analysis: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// runAnalyzeCut — минимальный вершинный разрез между start и end и пропускная способность
+func runAnalyzeCut(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: lem-in analyze cut map.txt")
+		os.Exit(1)
+	}
+	farm := parseG(args[0], lib.ParseOptions{})
+	cut := lib.MinVertexCut(farm)
+	if cut.Throughput == 0 {
+		fmt.Printf("%s is unreachable from %s\n", farm.End, farm.Start)
+		os.Exit(1)
+	}
+
+	coords := farm.Coords()
+	fmt.Printf("Minimum vertex cut between %s and %s: %d rooms\n", farm.Start, farm.End, len(cut.Rooms))
+	for _, name := range cut.Rooms {
+		fmt.Printf("  %s (%d, %d)\n", name, coords[name][0], coords[name][1])
+	}
+	if cut.Direct {
+		fmt.Printf("  plus the tunnel %s-%s, which no room can block\n", farm.Start, farm.End)
+	}
+	fmt.Printf("Max throughput: %d ants per turn in steady state\n", cut.Throughput)
+	fmt.Printf("Lower bound for %d ants: %d turns\n", farm.Ants, lib.LowerBound(farm))
+}
+
+// runAnalyzeLinks — какой новый тоннель сильнее всего сократит число ходов:
+// кандидаты из -add или все пары комнат на расстоянии не больше -within
+func runAnalyzeLinks(args []string) {
+	fs := flag.NewFlagSet("analyze links", flag.ExitOnError)
+	add := fs.String("add", "", "comma-separated candidate links, e.g. a-b,c-d")
+	within := fs.Float64("within", 0, "candidates: all unlinked room pairs within this coordinate distance")
+	k := fs.Int("k", 1, "how many best links to report")
+	solverName := fs.String("solver", "flow", "strategy used to count turns (flow is fast on many candidates)")
+	timeout := fs.Duration("timeout", 0, "maximum time for the whole analysis (0 — no limit)")
+	fs.Parse(args)
+	if fs.NArg() != 1 || (*add == "") == (*within <= 0) {
+		fmt.Println("Usage: lem-in analyze links (-add a-b,... | -within d) [-k n] [-solver name] map.txt")
+		os.Exit(1)
+	}
+	solver, err := lib.LookupSolver(*solverName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	farm := parseG(fs.Arg(0), lib.ParseOptions{})
+
+	var candidates [][2]string
+	if *add != "" {
+		for _, link := range strings.Split(*add, ",") {
+			a, b := lib.ParseLink(strings.TrimSpace(link))
+			if a == "" || b == "" {
+				fmt.Printf("Invalid link format: %s\n", link)
+				os.Exit(1)
+			}
+			if _, err := farm.WithLink(a, b); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			candidates = append(candidates, [2]string{a, b})
+		}
+	} else {
+		candidates = lib.CandidateLinks(farm, *within)
+	}
+	if len(candidates) == 0 {
+		fmt.Println("No candidate links.")
+		return
+	}
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	suggestions, err := lib.SuggestLinks(ctx, farm, candidates, solver)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	turns := func(n int) string {
+		if n == math.MaxInt {
+			return "no path"
+		}
+		return strconv.Itoa(n) + " turns"
+	}
+	fmt.Printf("%d candidate links, %d ants, %s now (solver %s)\n",
+		len(candidates), farm.Ants, turns(suggestions[0].Before), *solverName)
+	if suggestions[0].Gain() <= 0 {
+		fmt.Println("No single link reduces the number of turns.")
+		return
+	}
+	for i, s := range suggestions[:min(*k, len(suggestions))] {
+		if s.Gain() <= 0 {
+			break
+		}
+		gain := fmt.Sprintf("(-%d)", s.Gain())
+		if s.CreatesPath() {
+			gain = "(creates a path)"
+		}
+		fmt.Printf("%d. %s-%s: %s -> %s %s\n", i+1, s.From, s.To, turns(s.Before), turns(s.After), gain)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	lib "lem-in/helpers"
+)
+
+// benchResult — одна стратегия на одной карте; время и память — среднее на прогон
+type benchResult struct {
+	Map         string `json:"map"`
+	Solver      string `json:"solver"`
+	Ants        int    `json:"ants"`
+	Turns       int    `json:"turns"`
+	LowerBound  int    `json:"lowerBound"`
+	Gap         int    `json:"gap"`
+	NsPerOp     int64  `json:"nsPerOp"`
+	AllocsPerOp uint64 `json:"allocsPerOp"`
+	BytesPerOp  uint64 `json:"bytesPerOp"`
+	Error       string `json:"error,omitempty"`
+	TimedOut    bool   `json:"timedOut,omitempty"`
+}
+
+// benchMaps — карты корпуса: файлы как есть, из каталогов — *.txt и *.txt.gz
+func benchMaps(args []string) ([]string, error) {
+	var maps []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			maps = append(maps, arg)
+			continue
+		}
+		entries, err := os.ReadDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && isMapName(e.Name()) {
+				maps = append(maps, filepath.Join(arg, e.Name()))
+			}
+		}
+	}
+	sort.Strings(maps)
+	return maps, nil
+}
+
+// benchSolve — repeat прогонов стратегии; ходы первого проверяются CheckMoves
+func benchSolve(f *lib.Farm, solver lib.Solver, repeat int, timeout time.Duration) (res benchResult) {
+	var before, after runtime.MemStats
+	var elapsed time.Duration
+	var plan *lib.Plan
+	for i := 0; i < repeat; i++ {
+		ctx, cancel := context.Background(), func() {}
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		t0 := time.Now()
+		p, err := solver.Solve(ctx, f)
+		elapsed += time.Since(t0)
+		runtime.ReadMemStats(&after)
+		cancel()
+		if err != nil {
+			res.Error = err.Error()
+			res.TimedOut = errors.Is(err, context.DeadlineExceeded)
+			return res
+		}
+		res.AllocsPerOp += after.Mallocs - before.Mallocs
+		res.BytesPerOp += after.TotalAlloc - before.TotalAlloc
+		if plan == nil {
+			plan = p
+		}
+	}
+	res.NsPerOp = elapsed.Nanoseconds() / int64(repeat)
+	res.AllocsPerOp /= uint64(repeat)
+	res.BytesPerOp /= uint64(repeat)
+
+	turns, err := lib.CheckMoves(f, lib.PlanMoves(f, plan))
+	switch {
+	case len(plan.Paths) == 0:
+		res.Error = "no paths from start to end"
+	case err != nil:
+		res.Error = "invalid moves: " + err.Error()
+	case turns != plan.Turns:
+		res.Error = fmt.Sprintf("plan says %d turns, moves take %d", plan.Turns, turns)
+	}
+	res.Turns = turns
+	res.LowerBound = lib.LowerBound(f)
+	res.Gap = res.Turns - res.LowerBound
+	return res
+}
+
+// runBench — "lem-in bench examples/ -solvers=dfs,flow -repeat=5": сравнение
+// стратегий на корпусе карт; ходы каждой проверяются по правилам.
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	solverList := fs.String("solvers", "", "comma-separated strategies (default all)")
+	repeat := fs.Int("repeat", 1, "runs per solver and map")
+	timeout := fs.Duration("timeout", 0, "maximum time for one run (0 — no limit)")
+	asJSON := fs.Bool("json", false, "print results as JSON")
+	// флаги можно писать и после каталогов: bench examples/ -repeat=5
+	var paths []string
+	for fs.Parse(args); fs.NArg() > 0; fs.Parse(args) {
+		paths = append(paths, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(paths) == 0 || *repeat < 1 {
+		fmt.Println("Usage: lem-in bench [-solvers dfs,flow,...] [-repeat N] [-timeout d] [-json] maps-dir|map.txt...")
+		os.Exit(1)
+	}
+
+	var names []string
+	for _, name := range strings.Split(*solverList, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		for _, s := range lib.Solvers() {
+			names = append(names, s.Name)
+		}
+	}
+	solvers := make([]lib.Solver, len(names))
+	for i, name := range names {
+		s, err := lib.LookupSolver(name)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		solvers[i] = s
+	}
+
+	maps, err := benchMaps(paths)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	results := []benchResult{}
+	for _, m := range maps {
+		r, err := openMap(m)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", m, err)
+			continue
+		}
+		f, err := lib.ParseFarm(context.Background(), r, lib.ParseOptions{})
+		r.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: skipped: %v\n", m, err)
+			continue
+		}
+		for i, s := range solvers {
+			res := benchSolve(f, s, *repeat, *timeout)
+			res.Map, res.Solver, res.Ants = m, names[i], f.Ants
+			results = append(results, res)
+		}
+	}
+
+	failed := false
+	for _, r := range results {
+		failed = failed || r.Error != ""
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(results)
+	} else {
+		printBench(results)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printBench(results []benchResult) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "MAP\tSOLVER\tANTS\tTURNS\tBOUND\tGAP\tTIME\tALLOCS\tBYTES\t")
+	for _, r := range results {
+		if r.Error != "" {
+			status := "ERROR"
+			if r.TimedOut {
+				status = "TIMEOUT"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t\t\t\t\t\t%s\n", r.Map, r.Solver, r.Ants, status, r.Error)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%v\t%d\t%d\t\n", r.Map, r.Solver, r.Ants,
+			r.Turns, r.LowerBound, r.Gap, time.Duration(r.NsPerOp).Round(time.Microsecond), r.AllocsPerOp, r.BytesPerOp)
+	}
+	tw.Flush()
+}
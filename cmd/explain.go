@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	lib "lem-in/helpers"
+)
+
+// printExplanation — кандидаты стратегии dfs: пути, распределение муравьёв
+// (CombinedHeights), высота; победитель отмечен "*", ничьи объяснены
+func printExplanation(w io.Writer, f *lib.Farm, ex *lib.Explanation) {
+	fmt.Fprintf(w, "%d candidate groups for %d ants (height = path length + ants of the first, shortest path)\n\n",
+		len(ex.Candidates), f.Ants)
+	for i, c := range ex.Candidates {
+		mark := " "
+		if i == ex.Winner {
+			mark = "*"
+		}
+		fmt.Fprintf(w, "%s #%d  height %d, %d turns, %d paths", mark, i+1, c.Height, c.Turns, len(c.Paths))
+		if c.Duplicate >= 0 {
+			fmt.Fprintf(w, "  (same as #%d)\n", c.Duplicate+1)
+			continue
+		}
+		fmt.Fprintln(w)
+		for j, p := range c.Paths {
+			fmt.Fprintf(w, "      len %-3d ants %-4d combined %-4d %s-%s\n",
+				len(p), c.Ants[j], c.Combined[j], f.Start, strings.Join(p, "-"))
+		}
+	}
+	if ex.Winner < 0 {
+		fmt.Fprintln(w, "\nNo paths found from start to end.")
+		return
+	}
+
+	best := ex.Candidates[ex.Winner]
+	fmt.Fprintf(w, "\nWinner: #%d with height %d (%d turns).\n", ex.Winner+1, best.Height, best.Turns)
+	if len(ex.Tied) > 0 {
+		tied := make([]string, len(ex.Tied))
+		for i, t := range ex.Tied {
+			tied[i] = fmt.Sprintf("#%d", t+1)
+		}
+		fmt.Fprintf(w, "Tied with %s: the first minimum wins, and groups are built from seed paths\n", strings.Join(tied, ", "))
+		fmt.Fprintln(w, "sorted by length, then by room names, so the winner starts from the earliest seed.")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// stdin читается один раз во временный файл: карту нужно разобрать, а потом
+// вывести как есть, и stdin для этого не перечитать.
+var (
+	stdinOnce sync.Once
+	stdinCopy *os.File
+	stdinErr  error
+)
+
+func spoolStdin() (*os.File, error) {
+	stdinOnce.Do(func() {
+		f, err := os.CreateTemp("", "lem-in-stdin-*.txt")
+		if err != nil {
+			stdinErr = err
+			return
+		}
+		// файл доступен, пока открыт; на диске после выхода ничего не остаётся
+		os.Remove(f.Name())
+		if _, err := io.Copy(f, os.Stdin); err != nil {
+			f.Close()
+			stdinErr = err
+			return
+		}
+		stdinCopy = f
+	})
+	return stdinCopy, stdinErr
+}
+
+// isMapName — что CLI принимает как карту: .txt, .txt.gz или "-" (stdin)
+func isMapName(name string) bool {
+	return name == "-" || strings.HasSuffix(name, ".txt") || strings.HasSuffix(name, ".txt.gz")
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
+
+// openMap — открывает карту: "-" — stdin, *.gz — распаковка на лету.
+// Каждый вызов читает карту с начала.
+func openMap(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		f, err := spoolStdin()
+		if err != nil {
+			return nil, err
+		}
+		size, err := f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(io.NewSectionReader(f, 0, size)), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(name, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return gzipFile{zr, f}, nil
+	}
+	return f, nil
+}
+
+// readMap — текст карты целиком (для команд, которым нужен весь файл)
+func readMap(name string) ([]byte, error) {
+	r, err := openMap(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lib "lem-in/helpers"
+)
+
+const inputMap = "3\r\n##start\r\na 0 0\r\n##end\r\nb 1 0\r\na-b"
+
+func readAll(t *testing.T, name string) string {
+	t.Helper()
+	r, err := openMap(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestOpenMap(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "map.txt")
+	if err := os.WriteFile(plain, []byte(inputMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(inputMap))
+	zw.Close()
+	packed := filepath.Join(dir, "map.txt.gz")
+	if err := os.WriteFile(packed, gz.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// stdin подменяется файлом; openMap читает его один раз, а отдаёт сколько угодно
+	stdin, err := os.CreateTemp(dir, "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin.WriteString(inputMap)
+	stdin.Seek(0, io.SeekStart)
+	saved := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = saved }()
+
+	for _, name := range []string{plain, packed, "-", "-"} {
+		if got := readAll(t, name); got != inputMap {
+			t.Errorf("%s: got %q", name, got)
+		}
+	}
+	if _, err := openMap(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Fatal("missing file opened")
+	}
+	if _, err := openMap(filepath.Join(dir, "missing.txt.gz")); err == nil {
+		t.Fatal("missing .gz opened")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bad.txt.gz"), []byte(inputMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openMap(filepath.Join(dir, "bad.txt.gz")); err == nil {
+		t.Fatal("plain text opened as gzip")
+	}
+
+	// карта из stdin выводится так же, как из файла: \r\n -> \n, последняя строка без добавлений
+	r, err := openMap("-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var out bytes.Buffer
+	if err := lib.EchoMap(&out, r); err != nil {
+		t.Fatal(err)
+	}
+	if want := "3\n##start\na 0 0\n##end\nb 1 0\na-b\n\n"; out.String() != want {
+		t.Fatalf("echo: got %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	lib "lem-in/helpers"
+)
+
+// runItinerary — "lem-in itinerary [-csv] [-summary] map.txt": маршрут каждого
+// муравья (путь, ход выхода и прихода, ожидание) и сводка по времени в пути;
+// -summary — только сводка
+func runItinerary(args []string) {
+	fs := flag.NewFlagSet("itinerary", flag.ExitOnError)
+	solverName := fs.String("solver", lib.DefaultSolver, "path-finding strategy")
+	asCSV := fs.Bool("csv", false, "print CSV instead of a table")
+	summary := fs.Bool("summary", false, "print only the travel time summary")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: lem-in itinerary [-solver name] [-csv] [-summary] map.txt")
+		os.Exit(1)
+	}
+
+	farm := parseG(fs.Arg(0), lib.ParseOptions{})
+	plan, err := solveFarm(context.Background(), farm, *solverName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	its := lib.Itineraries(lib.PlanMoves(farm, plan))
+	stats := lib.ItineraryStats(its)
+
+	if *asCSV {
+		if err := writeItineraryCSV(os.Stdout, farm.Start, its, stats, *summary); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *summary {
+		fmt.Println(itinerarySummary(its, stats))
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ANT\tDEPART\tARRIVE\tTRAVEL\tWAITS\tPATH")
+	for _, it := range its {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s-%s\n", it.Ant, it.Departure, it.Arrival, it.Travel(), it.Waits,
+			farm.Start, strings.Join(it.Path, "-"))
+	}
+	tw.Flush()
+	fmt.Printf("\n%s\n", itinerarySummary(its, stats))
+}
+
+// writeItineraryCSV — по строке на муравья, с summary — вместо них сводка
+// строками "stat,value", чтобы CSV оставался одной таблицей
+func writeItineraryCSV(out io.Writer, start string, its []lib.Itinerary, stats lib.TravelStats, summary bool) error {
+	w := csv.NewWriter(out)
+	if summary {
+		w.Write([]string{"stat", "value"})
+		w.Write([]string{"ants", strconv.Itoa(len(its))})
+		w.Write([]string{"mean", strconv.FormatFloat(stats.Mean, 'f', 2, 64)})
+		w.Write([]string{"median", strconv.FormatFloat(stats.Median, 'f', 1, 64)})
+		w.Write([]string{"max", strconv.Itoa(stats.Max)})
+	} else {
+		w.Write([]string{"ant", "path", "departure", "arrival", "travel", "waits"})
+		for _, it := range its {
+			w.Write([]string{it.Ant, start + "-" + strings.Join(it.Path, "-"), strconv.Itoa(it.Departure),
+				strconv.Itoa(it.Arrival), strconv.Itoa(it.Travel()), strconv.Itoa(it.Waits)})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func itinerarySummary(its []lib.Itinerary, stats lib.TravelStats) string {
+	return fmt.Sprintf("%d ants, travel time: mean %.2f, median %.1f, max %d turns", len(its), stats.Mean, stats.Median, stats.Max)
+}
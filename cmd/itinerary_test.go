@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	lib "lem-in/helpers"
+)
+
+func TestItineraryCSV(t *testing.T) {
+	its := lib.Itineraries([][]string{{"L1-a", "L2-b"}, {"L1-e"}, {"L2-e"}})
+	stats := lib.ItineraryStats(its)
+	tests := []struct {
+		summary bool
+		want    string
+	}{
+		{false, "ant,path,departure,arrival,travel,waits\nL1,s-a-e,1,2,2,0\nL2,s-b-e,1,3,3,1\n"},
+		{true, "stat,value\nants,2\nmean,2.50\nmedian,2.5\nmax,3\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := writeItineraryCSV(&out, "s", its, stats, tt.summary); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("summary=%v:\ngot  %q\nwant %q", tt.summary, out.String(), tt.want)
+		}
+	}
+}
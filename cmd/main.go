@@ -1,418 +1,324 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
+	"time"
 
 	lib "lem-in/helpers"
 )
 
-// Graph — карта: имя комнаты -> список соседей
-type Graph map[string][]string
-
-func (g Graph) addRoom(name string) {
-	if _, exists := g[name]; !exists {
-		g[name] = []string{}
-	} else {
-		fmt.Printf("Room %s already exists\n", name)
-		fmt.Printf("Wrong format in graph.txt\n")
-		os.Exit(1)
-	}
-}
-
-func (g Graph) addLink(from, to string) {
-	g[from] = append(g[from], to)
-	g[to] = append(g[to], from)
-}
-
-// parseG — читает и валидирует файл, возвращает граф, n, start, end.
-func parseG(fileName string) (Graph, int, string, string) {
-	g := Graph{}
-
-	data, err := os.ReadFile(fileName)
+// parseG — потоково читает и валидирует карту (файл, .txt.gz или "-"), возвращает
+// новую ферму (без решения). При ошибке печатает её и завершает программу.
+func parseG(fileName string, opts lib.ParseOptions) *lib.Farm {
+	file, err := openMap(fileName)
 	if err != nil {
 		fmt.Println("Ошибка чтения файла:", err)
 		os.Exit(1)
 	}
-	text := strings.ReplaceAll(string(data), "\r\n", "\n")
-	lines := strings.Split(text, "\n")
+	defer file.Close()
 
-	if len(lines) == 0 {
-		fmt.Println("No data in graph.txt")
+	farm, err := lib.ParseFarm(context.Background(), file, opts)
+	if err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
+	return farm
+}
 
-	// защититься от возможного '\r' в конце первой строки
-	if len(lines[0]) > 0 && lines[0][len(lines[0])-1] == '\r' {
-		lines[0] = lines[0][:len(lines[0])-1]
+// solveFarm — решает ферму выбранной стратегией и сохраняет группу путей в f.Group.
+// Если путей нет, печатает ошибку и завершает программу.
+func solveFarm(ctx context.Context, f *lib.Farm, solverName string) (*lib.Plan, error) {
+	solver, err := lib.LookupSolver(solverName)
+	if err != nil {
+		return nil, err
 	}
-
-	// первая строка — количество муравьёв
-	n, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	plan, err := solver.Solve(ctx, f)
 	if err != nil {
-		fmt.Println("Invalid number of ants:", lines[0])
-		os.Exit(1)
+		return nil, err
 	}
-	// теперь требуем строго положительное число (>0)
-	if n <= 0 {
-		fmt.Println("Number of ants must be a positive integer")
+	if len(plan.Paths) == 0 {
+		fmt.Println("No paths found from start to end.")
+		fmt.Println("\n\t\tERROR: No valid input data found")
 		os.Exit(1)
 	}
+	f.Group = plan.Paths
+	return plan, nil
+}
 
-	start, end := "", ""
-	flag := "room"
-
-	// парсим остальные строки
-	for _, raw := range lines[1:] {
-		line := strings.TrimSpace(raw)
-		if line == "" {
-			continue
-		}
-
-		// пропускаем комментарии одной решётки: "#something"
-		// но обрабатываем директивы "##start" и "##end"
-		if strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "##") {
-			continue
-		}
-
-		// директивы типа "##start" / "##end"
-		if strings.HasPrefix(line, "##") {
-			switch strings.TrimSpace(line) {
-			case "##start":
-				flag = "start"
-				continue
-			case "##end":
-				flag = "end"
-				continue
-			default:
-				continue
-			}
-		}
-
-		// описание комнаты: name X Y
-		parts := strings.Fields(line)
-		if len(parts) == 3 {
-			roomName := parts[0]
-			if roomName == "" {
-				fmt.Println("Invalid room name in line:", line)
-				os.Exit(1)
-			}
-			if _, err := strconv.Atoi(parts[1]); err != nil {
-				fmt.Println("Invalid X coordinate for room", roomName)
-				os.Exit(1)
-			}
-			if _, err := strconv.Atoi(parts[2]); err != nil {
-				fmt.Println("Invalid Y coordinate for room", roomName)
-				os.Exit(1)
-			}
-
-			lib.RoomNames = append(lib.RoomNames, roomName)
-			lib.Matrix = append(lib.Matrix, []string{parts[1], parts[2]})
-
-			g.addRoom(roomName)
-
-			if flag == "start" {
-				start = roomName
-				flag = "room"
-			} else if flag == "end" {
-				end = roomName
-				flag = "room"
-			}
-			continue
-		}
-
-		// описание связи: A-B
-		if strings.Contains(line, "-") && !strings.Contains(line, " ") {
-			room1, room2 := lib.ParseLink(line)
-
-			if room1 == "" || room2 == "" {
-				fmt.Printf("Invalid link format: %s\n", line)
-				os.Exit(1)
-			}
-			if room1 == room2 {
-				fmt.Printf("Invalid link: room %s cannot be linked to itself\n", room1)
-				os.Exit(1)
-			}
-			if _, ok := g[room1]; !ok {
-				fmt.Printf("Invalid link: room %s is not defined\n", room1)
-				os.Exit(1)
-			}
-			if _, ok := g[room2]; !ok {
-				fmt.Printf("Invalid link: room %s is not defined\n", room2)
-				os.Exit(1)
-			}
-			if lib.Contains(g[room1], room2) {
-				fmt.Printf("Invalid link: duplicate link %s-%s\n", room1, room2)
-				os.Exit(1)
-			}
-
-			g.addLink(room1, room2)
-			continue
-		}
-
-		// иначе неверный формат
-		fmt.Printf("Wrong format in graph.txt near line: %s\n", line)
-		os.Exit(1)
+// printSolveError — причина остановки решения и найденные к этому моменту пути
+func printSolveError(w io.Writer, err error, timeout time.Duration) {
+	var se *lib.SolveError
+	if !errors.As(err, &se) {
+		fmt.Fprintln(w, err)
+		return
 	}
-
-	// start и end обязаны быть заданы
-	if start == "" {
-		fmt.Println("Start room not defined")
-		os.Exit(1)
+	if errors.Is(se.Err, context.DeadlineExceeded) {
+		fmt.Fprintf(w, "Solve timed out after %v: found %d paths so far\n", timeout, se.Found)
+	} else {
+		fmt.Fprintf(w, "Solve stopped (%v): found %d paths so far\n", se.Err, se.Found)
 	}
-	if end == "" {
-		fmt.Println("End room not defined")
-		os.Exit(1)
+	for _, p := range se.Paths {
+		fmt.Fprintln(w, " ", strings.Join(p, "-"))
 	}
-
-	return g, n, start, end
 }
 
-// простой DFS, возвращает все простые пути от start до end
-func (g Graph) getBestGroup(start, end string) [][]string {
-	ans := [][]string{}
-	path := []string{start}
-
-	var dfs func(string)
-	dfs = func(current string) {
-		if current == end {
-			ans = append(ans, append([]string{}, path...))
-			return
-		}
-		for _, neighbor := range g[current] {
-			if !lib.Contains(path, neighbor) {
-				path = append(path, neighbor)
-				dfs(neighbor)
-				path = path[:len(path)-1]
-			}
-		}
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("No input file specified.")
+		return
 	}
-	dfs(start)
-	return ans
-}
-
-// распределяем муравьёв: создаём набор групп путей и выбираем лучший по высоте.
-// Добавлена сортировка путей для детерминизма.
-func (g Graph) sendTheAnts(n int, start, end string) [][]string {
-	paths := g.getBestGroup(start, end)
-	if len(paths) == 0 {
-		fmt.Println("No paths found from start to end.")
+	switch os.Args[1] {
+	case "convert":
+		runConvert(os.Args[2:])
+		return
+	case "fmt":
+		runFmt(os.Args[2:])
+		return
+	case "lint":
+		runLint(os.Args[2:])
+		return
+	case "play":
+		runPlay(os.Args[2:])
+		return
+	case "render":
+		runRender(os.Args[2:])
+		return
+	case "report":
+		runReport(os.Args[2:])
+		return
+	case "bench":
+		runBench(os.Args[2:])
+		return
+	case "itinerary":
+		runItinerary(os.Args[2:])
+		return
+	case "utilisation":
+		runUtilisation(os.Args[2:])
+		return
+	case "analyze":
+		runAnalyze(os.Args[2:])
+		return
 	}
-
-	// сортируем пути: сначала по длине (короче лучше), потом лексикографически
-	sort.Slice(paths, func(i, j int) bool {
-		if len(paths[i]) != len(paths[j]) {
-			return len(paths[i]) < len(paths[j])
+	timeout := flag.Duration("timeout", 0, "maximum time to solve the map (0 — no limit)")
+	maxRooms := flag.Int("max-rooms", 0, "reject maps with more rooms (0 — no limit)")
+	maxLinks := flag.Int("max-links", 0, "reject maps with more links (0 — no limit)")
+	maxLine := flag.Int("max-line", lib.DefaultMaxLineLength, "maximum input line length in bytes")
+	progress := flag.Bool("progress", false, "report parsing progress on stderr")
+	solverName := flag.String("solver", lib.DefaultSolver, "path-finding strategy (see -solvers)")
+	listSolvers := flag.Bool("solvers", false, "list available strategies")
+	explain := flag.Bool("explain", false, "print every candidate path group of the dfs strategy and why the winner was chosen")
+	flag.Parse()
+	if *listSolvers {
+		for _, s := range lib.Solvers() {
+			fmt.Printf("%-8s %s\n", s.Name, s.Description)
 		}
-		return strings.Join(paths[i], ",") < strings.Join(paths[j], ",")
-	})
+		return
+	}
+	if flag.NArg() < 1 {
+		fmt.Println("No input file specified.")
+		return
+	}
+	fileName := flag.Arg(0)
+	if !isMapName(fileName) {
+		fmt.Println("Input file must have a .txt or .txt.gz extension (or - for stdin).")
+		return
+	}
 
-	// теперь убираем старт и конец (как у вас было)
-	for i, p := range paths {
-		if len(p) >= 2 {
-			paths[i] = p[1 : len(p)-1]
-		} else {
-			paths[i] = []string{}
+	// сначала парсим и валидируем — если есть ошибка, parseG сделает os.Exit(1)
+	opts := lib.ParseOptions{MaxRooms: *maxRooms, MaxLinks: *maxLinks, MaxLineLength: *maxLine}
+	if *progress {
+		opts.Progress = func(s lib.ParseStats) {
+			fmt.Fprintf(os.Stderr, "parsed %d lines (%d MB): %d rooms, %d links\n",
+				s.Lines, s.Bytes>>20, s.Rooms, s.Links)
 		}
 	}
-
-	// ваша исходная логика объединения путей по конфликтам
-	for i := 0; i < len(paths); i++ {
-		for ii := i + 1; ii < len(paths); ii++ {
-			if len(paths[ii-1]) > len(paths[ii]) {
-				paths[ii-1], paths[ii] = paths[ii], paths[ii-1]
-			}
+	farm := parseG(fileName, opts)
+
+	// решаем до вывода файла, чтобы при таймауте не печатать половину результата
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	if *explain {
+		if *solverName != lib.DefaultSolver {
+			fmt.Printf("-explain is only available for the %s solver\n", lib.DefaultSolver)
+			os.Exit(1)
+		}
+		ex, err := lib.ExplainGroups(ctx, farm)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
 		}
+		printExplanation(os.Stdout, farm, ex)
+		return
+	}
+	plan, err := solveFarm(ctx, farm, *solverName)
+	if err != nil {
+		printSolveError(os.Stdout, err, *timeout)
+		os.Exit(1)
 	}
 
-	ans := [][][]string{}
-	for i := 0; i < len(paths); i++ {
-		path2 := [][]string{paths[i]}
-		for ii := i + 1; ii < len(paths); ii++ {
-			if !lib.Conflicts(path2, paths[ii]) {
-				path2 = append(path2, paths[ii])
-			}
-		}
-		ans = append(ans, path2)
+	// после успешного решения — выводим исходный файл (количество муравьёв + остальное)
+	if file, err := openMap(fileName); err == nil {
+		lib.EchoMap(os.Stdout, file)
+		file.Close()
 	}
 
-	for i, group := range ans {
-		for j, p := range group {
-			ans[i][j] = append(p, end)
+	// симуляция и печать шагов (ваша логика сохранена)
+	for _, turn := range lib.PlanMoves(farm, plan) {
+		for _, move := range turn {
+			fmt.Printf("%s ", move)
 		}
+		fmt.Println()
 	}
-
-	return bestGroupByHeight(ans, n)
 }
 
-// вспомогательные функции
-func getCombinedHeights(group [][]string, n int) []int {
-	heights := make([]int, len(group))
-	for i, k := range group {
-		heights[i] = len(k)
-	}
-	for i := 0; i < n; i++ {
-		midIndex := lib.IndexOfMin(heights)
-		heights[midIndex]++
+// runConvert — "lem-in convert graph.json": JSON из graph-redactor -> карта lem-in;
+// файл читается как карта: "-" — stdin, *.gz — со сжатием
+func runConvert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	ants := fs.Int("ants", 0, "number of ants (overrides the editor label)")
+	out := fs.String("o", "", "output file (default stdout)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: lem-in convert [-ants N] [-o map.txt] graph.json")
+		os.Exit(1)
 	}
-	return heights
-}
 
-func getHeight(group [][]string, n int) (int, []int) {
-	heights := getCombinedHeights(group, n)
-	if len(heights) == 0 {
-		return 0, nil
+	data, err := readMap(fs.Arg(0))
+	if err != nil {
+		fmt.Println("Ошибка чтения файла:", err)
+		os.Exit(1)
 	}
-	return heights[0], heights
-}
-
-func bestGroupByHeight(groups [][][]string, n int) [][]string {
-	heights := make([]int, len(groups))
-	for i, group := range groups {
-		heights[i], _ = getHeight(group, n)
+	text, err := lib.ConvertRedactor(data, *ants)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
-
-	minIndex := lib.IndexOfMin(heights)
-	if minIndex == -1 {
-		fmt.Println("\n\t\tERROR: No valid input data found")
+	if *out == "" {
+		fmt.Print(text)
+		return
+	}
+	if err := os.WriteFile(*out, []byte(text), 0o644); err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
-	return groups[minIndex]
 }
 
-func getPathHeights(group [][]string) []int {
-	heights := make([]int, len(group))
-	for i, path := range group {
-		heights[i] = len(path)
+// runFmt — "lem-in fmt [-w] map.txt": печатает карту в каноническом виде
+// или с -w перезаписывает файл (кроме stdin и *.gz).
+func runFmt(args []string) {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := fs.Bool("w", false, "write result to the file instead of stdout")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fmt.Println("Usage: lem-in fmt [-w] map.txt...")
+		os.Exit(1)
 	}
-	return heights
-}
 
-func getAntHeights(combined, heights []int) []int {
-	for i := range combined {
-		combined[i] -= heights[i]
+	failed := false
+	for _, fileName := range fs.Args() {
+		if *write && (fileName == "-" || strings.HasSuffix(fileName, ".gz")) {
+			fmt.Printf("%s: -w cannot rewrite stdin or a compressed map\n", fileName)
+			failed = true
+			continue
+		}
+		data, err := readMap(fileName)
+		if err != nil {
+			fmt.Println("Ошибка чтения файла:", err)
+			failed = true
+			continue
+		}
+		text, err := lib.FormatMap(string(data))
+		if err != nil {
+			fmt.Printf("%s: %v\n", fileName, err)
+			failed = true
+			continue
+		}
+		if !*write {
+			fmt.Print(text)
+			continue
+		}
+		if text == string(data) {
+			continue
+		}
+		if err := os.WriteFile(fileName, []byte(text), 0o644); err != nil {
+			fmt.Println(err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
 	}
-	return combined
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("No input file specified.")
+// runLint — "lem-in lint [-disable rule,...] map.txt": жёсткие ошибки проверяет parseG,
+// затем выводятся предупреждения; код выхода 1, если они есть.
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	disable := fs.String("disable", "", "comma-separated rule IDs to skip")
+	list := fs.Bool("rules", false, "list available rules")
+	fs.Parse(args)
+
+	if *list {
+		ids := make([]string, 0, len(lib.LintRules))
+		for id := range lib.LintRules {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			fmt.Printf("%-20s %s\n", id, lib.LintRules[id])
+		}
 		return
 	}
-	fileName := os.Args[1]
-	if !strings.HasSuffix(fileName, ".txt") {
-		fmt.Println("Input file must have a .txt extension.")
-		return
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: lem-in lint [-disable rule,...] [-rules] map.txt")
+		os.Exit(1)
 	}
 
-	// сначала парсим и валидируем — если есть ошибка, parseG сделает os.Exit(1)
-	g, n, start, end := parseG(fileName)
-
-	// после успешного парсинга — выводим исходный файл (количество муравьёв + остальное)
-	dataRaw, err := os.ReadFile(fileName)
-	if err == nil {
-		dataStr := strings.ReplaceAll(string(dataRaw), "\r\n", "\n")
-		lines := strings.Split(dataStr, "\n")
-		if len(lines) > 0 && strings.TrimSpace(lines[0]) != "" {
-			fmt.Println(lines[0])
+	disabled := map[string]bool{}
+	for _, id := range strings.Split(*disable, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
 		}
-		if len(lines) > 1 {
-			fmt.Print(strings.Join(lines[1:], "\n"))
+		if _, ok := lib.LintRules[id]; !ok {
+			fmt.Printf("Unknown lint rule: %s\n", id)
+			os.Exit(1)
 		}
-		fmt.Println()
-		fmt.Println()
+		disabled[id] = true
 	}
 
-	// симуляция и печать шагов (ваша логика сохранена)
-	bestGroup := g.sendTheAnts(n, start, end)
-	height, combined := getHeight(bestGroup, n)
-	heights := getPathHeights(bestGroup)
-	antProxy := getAntHeights(combined, heights)
-
-	counter := 0
-	ants := make([]lib.Ant, 0)
+	fileName := fs.Arg(0)
+	parseG(fileName, lib.ParseOptions{})
 
-	for i := range bestGroup {
-		if n > 0 && i < len(antProxy) && antProxy[i] > 0 {
-			counter++
-			ant := lib.Ant{
-				Name:    fmt.Sprintf("L%d", counter),
-				Current: 0,
-				Path:    bestGroup[i],
-			}
-			ants = append(ants, ant)
-			n--
-			antProxy[i]--
-		}
+	data, err := readMap(fileName)
+	if err != nil {
+		fmt.Println("Ошибка чтения файла:", err)
+		os.Exit(1)
 	}
-
-	for height > 1 {
-		height--
-		structAnt := []lib.Ant{}
-		for i := range ants {
-			fmt.Printf("%s-%s ", ants[i].Name, ants[i].Path[ants[i].Current])
-			structAnt = append(structAnt, ants[i])
-			ants[i].Current++
-		}
-		for i := len(ants) - 1; i >= 0; i-- {
-			if ants[i].Current >= len(ants[i].Path) {
-				ants = append(ants[:i], ants[i+1:]...)
-			}
-		}
-		for i := 0; i < len(antProxy) && n > 0; i++ {
-			if antProxy[i] > 0 {
-				counter++
-				ant := lib.Ant{
-					Name:    fmt.Sprintf("L%d", counter),
-					Current: 0,
-					Path:    bestGroup[i],
-				}
-				ants = append(ants, ant)
-				n--
-				antProxy[i]--
-			}
-		}
-		fmt.Println()
+	warns, err := lib.LintMap(string(data), disabled)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
-
-	for len(ants) > 0 || n > 0 {
-		structAnt := []lib.Ant{}
-		for i := range ants {
-			if ants[i].Current < len(ants[i].Path) {
-				fmt.Printf("%s-%s ", ants[i].Name, ants[i].Path[ants[i].Current])
-				structAnt = append(structAnt, ants[i])
-				ants[i].Current++
-			}
-		}
-		for i := len(ants) - 1; i >= 0; i-- {
-			if ants[i].Current >= len(ants[i].Path) {
-				ants = append(ants[:i], ants[i+1:]...)
-			}
-		}
-		for i := 0; i < len(antProxy) && n > 0; i++ {
-			if antProxy[i] > 0 {
-				counter++
-				ant := lib.Ant{
-					Name:    fmt.Sprintf("L%d", counter),
-					Current: 0,
-					Path:    bestGroup[i],
-				}
-				ants = append(ants, ant)
-				n--
-				antProxy[i]--
-			}
-		}
-		fmt.Println()
-		if n > 0 {
-			fmt.Printf("Remaining ants to send: %d\n", n)
-		}
-		if len(ants) == 0 && n > 0 {
-			fmt.Printf("Warning: %d ants couldn't be sent (no available paths)\n", n)
-			break
+	for _, w := range warns {
+		if w.Line > 0 {
+			fmt.Printf("%s:%s\n", fileName, w)
+		} else {
+			fmt.Printf("%s: %s\n", fileName, w)
 		}
 	}
+	if len(warns) > 0 {
+		os.Exit(1)
+	}
 }
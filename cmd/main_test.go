@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lib "lem-in/helpers"
+)
+
+func TestPrintSolveError(t *testing.T) {
+	paths := [][]string{{"s", "a", "e"}, {"s", "b", "e"}}
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&lib.SolveError{Err: context.DeadlineExceeded, Found: 5, Paths: paths},
+			"Solve timed out after 2s: found 5 paths so far\n  s-a-e\n  s-b-e\n"},
+		{&lib.SolveError{Err: context.Canceled, Found: 2, Paths: paths},
+			"Solve stopped (context canceled): found 2 paths so far\n  s-a-e\n  s-b-e\n"},
+		{errors.New("unknown solver"), "unknown solver\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		printSolveError(&out, tt.err, 2*time.Second)
+		if out.String() != tt.want {
+			t.Errorf("got %q, want %q", out.String(), tt.want)
+		}
+	}
+}
+
+// gridMap — решётка n×n: путей столько, что dfs не успевает за таймаут
+func gridMap(n int) string {
+	var b strings.Builder
+	b.WriteString("10\n")
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			switch {
+			case x == 0 && y == 0:
+				b.WriteString("##start\n")
+			case x == n-1 && y == n-1:
+				b.WriteString("##end\n")
+			}
+			fmt.Fprintf(&b, "r%d_%d %d %d\n", x, y, x, y)
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if x+1 < n {
+				fmt.Fprintf(&b, "r%d_%d-r%d_%d\n", x, y, x+1, y)
+			}
+			if y+1 < n {
+				fmt.Fprintf(&b, "r%d_%d-r%d_%d\n", x, y, x, y+1)
+			}
+		}
+	}
+	return b.String()
+}
+
+// TestTimeoutExit — CLI с -timeout: код выхода 1, сообщение о таймауте,
+// не больше MaxReportedPaths путей и никакого вывода карты
+func TestTimeoutExit(t *testing.T) {
+	if os.Getenv("LEMIN_RUN_MAIN") == "1" {
+		os.Args = append([]string{"lem-in"}, strings.Fields(os.Getenv("LEMIN_ARGS"))...)
+		main()
+		os.Exit(0)
+	}
+	file := filepath.Join(t.TempDir(), "grid.txt")
+	if err := os.WriteFile(file, []byte(gridMap(7)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestTimeoutExit$")
+	cmd.Env = append(os.Environ(), "LEMIN_RUN_MAIN=1", "LEMIN_ARGS=-timeout=100ms "+file)
+	start := time.Now()
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		t.Fatalf("err = %v, output %q", err, out)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("timed out solve took %v", elapsed)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if !strings.HasPrefix(lines[0], "Solve timed out after 100ms: found ") {
+		t.Fatalf("first line %q", lines[0])
+	}
+	if len(lines)-1 > lib.MaxReportedPaths {
+		t.Fatalf("%d paths printed", len(lines)-1)
+	}
+	for _, l := range lines[1:] {
+		if !strings.HasPrefix(l, "  r0_0-") {
+			t.Fatalf("unexpected line %q", l)
+		}
+	}
+}
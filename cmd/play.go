@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	lib "lem-in/helpers"
+)
+
+// runPlay — "lem-in play map.txt": анимация муравьёв прямо в терминале.
+// Управление: пробел — пауза, ←/→ — шаг назад/вперёд, +/- — скорость, q — выход.
+func runPlay(args []string) {
+	fs := flag.NewFlagSet("play", flag.ExitOnError)
+	delay := fs.Duration("delay", 700*time.Millisecond, "time between turns")
+	solverName := fs.String("solver", lib.DefaultSolver, "path-finding strategy")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: lem-in play [-delay 700ms] [-solver name] map.txt")
+		os.Exit(1)
+	}
+
+	farm := parseG(fs.Arg(0), lib.ParseOptions{})
+	plan, err := solveFarm(context.Background(), farm, *solverName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	turns := lib.PlanMoves(farm, plan)
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		fmt.Println("play requires a terminal:", err)
+		os.Exit(1)
+	}
+	defer tty.Close()
+
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		fmt.Println("play requires a terminal and the stty utility:", err)
+		os.Exit(1)
+	}
+	stty(tty, "raw", "-echo")
+	fmt.Fprint(tty, "\033[?1049h\033[?25l")
+	defer func() {
+		fmt.Fprint(tty, "\033[?25h\033[?1049l")
+		stty(tty, strings.TrimSpace(saved))
+	}()
+
+	keys := make(chan string)
+	go readKeys(tty, keys)
+
+	p := &player{
+		g: farm.Links, n: farm.Ants, start: farm.Start, end: farm.End,
+		rooms: farm.Rooms, turns: turns,
+		delay: *delay, paused: false,
+	}
+	ticker := time.NewTicker(p.delay)
+	defer ticker.Stop()
+
+	for {
+		p.draw(tty)
+		select {
+		case <-ticker.C:
+			if p.paused {
+				continue
+			}
+			if p.turn < len(p.turns) {
+				p.turn++
+			}
+			if p.turn == len(p.turns) {
+				p.paused = true
+			}
+		case key, ok := <-keys:
+			if !ok {
+				return
+			}
+			switch key {
+			case "q":
+				return
+			case " ":
+				if p.turn == len(p.turns) {
+					p.turn = 0
+				}
+				p.paused = !p.paused
+			case "right":
+				p.paused = true
+				p.turn = min(p.turn+1, len(p.turns))
+			case "left":
+				p.paused = true
+				p.turn = max(p.turn-1, 0)
+			case "+":
+				p.delay = max(p.delay/2, 50*time.Millisecond)
+				ticker.Reset(p.delay)
+			case "-":
+				p.delay = min(p.delay*2, 5*time.Second)
+				ticker.Reset(p.delay)
+			}
+		}
+	}
+}
+
+type player struct {
+	g          map[string][]string // комната -> соседи (farm.Links)
+	n          int
+	start, end string
+	rooms      []lib.Room // в порядке объявления — в нём же рисуются подписи
+	turns      [][]string
+	turn       int
+	delay      time.Duration
+	paused     bool
+}
+
+type cell struct {
+	ch    rune
+	style string // ANSI-последовательность перед символом
+}
+
+func (p *player) draw(tty *os.File) {
+	cols, rows := termSize(tty)
+	cols-- // последний столбец не трогаем, чтобы терминал не переносил строку
+	h := rows - 2
+	grid := make([][]cell, h)
+	for i := range grid {
+		grid[i] = make([]cell, cols)
+		for j := range grid[i] {
+			grid[i][j] = cell{ch: ' '}
+		}
+	}
+
+	// масштабируем координаты комнат под окно
+	minX, minY, maxX, maxY := math.MaxInt, math.MaxInt, math.MinInt, math.MinInt
+	nameLen := 1
+	for _, r := range p.rooms {
+		minX, maxX = min(minX, r.X), max(maxX, r.X)
+		minY, maxY = min(minY, r.Y), max(maxY, r.Y)
+		nameLen = max(nameLen, len([]rune(r.Name))+3)
+	}
+	marginX := nameLen/2 + 1
+	scale := func(v, lo, hi, size, margin int) int {
+		if hi == lo || size <= 2*margin {
+			return size / 2
+		}
+		return margin + int(math.Round(float64(v-lo)*float64(size-1-2*margin)/float64(hi-lo)))
+	}
+	pos := map[string][2]int{}
+	for _, r := range p.rooms {
+		pos[r.Name] = [2]int{scale(r.X, minX, maxX, cols, marginX), scale(r.Y, minY, maxY, h, 1)}
+	}
+
+	// связи
+	for _, r := range p.rooms {
+		for _, to := range p.g[r.Name] {
+			if r.Name < to {
+				drawLine(grid, pos[r.Name], pos[to])
+			}
+		}
+	}
+
+	// муравьи по комнатам
+	ants := lib.AntsAt(p.turns, p.start, p.turn)
+	inRoom := map[string][]string{}
+	for _, a := range ants {
+		inRoom[a.Room] = append(inRoom[a.Room], a.Name)
+	}
+	atStart := p.n - len(ants)
+
+	// подписи в порядке комнат: при наложении кадр от кадра не меняется
+	for _, r := range p.rooms {
+		name, c := r.Name, pos[r.Name]
+		label, style := name, "\033[1m"
+		switch {
+		case name == p.start:
+			label, style = fmt.Sprintf("%s·%d", name, atStart), "\033[1;30;42m"
+		case name == p.end:
+			label, style = fmt.Sprintf("%s·%d", name, len(inRoom[name])), "\033[1;30;41m"
+		case len(inRoom[name]) > 0:
+			ant := inRoom[name][0]
+			col := lib.AntColor(ant)
+			label, style = ant, fmt.Sprintf("\033[1;30;48;2;%d;%d;%dm", col.R, col.G, col.B)
+		}
+		putText(grid, c[0]-len([]rune(label))/2, c[1], label, style)
+	}
+
+	state := "▶ playing"
+	if p.paused {
+		state = "⏸ paused"
+	}
+	status := fmt.Sprintf(" Turn %d/%d  %s  %v/turn   [space] pause  [←/→] step  [+/-] speed  [q] quit",
+		p.turn, len(p.turns), state, p.delay)
+	moves := ""
+	if p.turn > 0 {
+		moves = " " + strings.Join(p.turns[p.turn-1], " ")
+	}
+
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for _, row := range grid {
+		cur := ""
+		for _, c := range row {
+			if c.style != cur {
+				b.WriteString("\033[0m")
+				b.WriteString(c.style)
+				cur = c.style
+			}
+			b.WriteRune(c.ch)
+		}
+		b.WriteString("\033[0m\r\n")
+	}
+	b.WriteString("\033[2K" + truncate(moves, cols) + "\r\n")
+	b.WriteString("\033[2K\033[7m" + truncate(status, cols) + "\033[0m")
+	fmt.Fprint(tty, b.String())
+}
+
+// drawLine — Брезенхем; символ выбираем по наклону всего отрезка
+func drawLine(grid [][]cell, a, b [2]int) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	ch := '─'
+	switch {
+	case dx == 0 || math.Abs(float64(dy)/float64(dx)) > 2:
+		ch = '│'
+	case math.Abs(float64(dy)/float64(dx)) >= 0.5:
+		if (dx > 0) == (dy > 0) {
+			ch = '╲'
+		} else {
+			ch = '╱'
+		}
+	}
+
+	x, y := a[0], a[1]
+	sx, sy := 1, 1
+	if dx < 0 {
+		sx = -1
+	}
+	if dy < 0 {
+		sy = -1
+	}
+	adx, ady := abs(dx), abs(dy)
+	e := adx - ady
+	for {
+		if y >= 0 && y < len(grid) && x >= 0 && x < len(grid[y]) {
+			grid[y][x] = cell{ch: ch, style: "\033[90m"}
+		}
+		if x == b[0] && y == b[1] {
+			return
+		}
+		e2 := 2 * e
+		if e2 > -ady {
+			e -= ady
+			x += sx
+		}
+		if e2 < adx {
+			e += adx
+			y += sy
+		}
+	}
+}
+
+func putText(grid [][]cell, x, y int, text, style string) {
+	if y < 0 || y >= len(grid) {
+		return
+	}
+	for _, r := range text {
+		if x >= 0 && x < len(grid[y]) {
+			grid[y][x] = cell{ch: r, style: style}
+		}
+		x++
+	}
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// stty — настройки терминала через системную утилиту stty (POSIX, есть в
+// Linux и macOS): в стандартной библиотеке нет переносимого raw-режима, а
+// ioctl под каждую ОС play не стоит
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+func termSize(tty *os.File) (int, int) {
+	out, err := stty(tty, "size")
+	if err == nil {
+		var rows, cols int
+		if _, err := fmt.Sscan(out, &rows, &cols); err == nil && rows > 3 && cols > 10 {
+			return cols, rows
+		}
+	}
+	return 80, 24
+}
+
+// readKeys — разбирает ввод в raw-режиме, включая escape-последовательности стрелок
+func readKeys(tty *os.File, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := tty.Read(buf)
+		if err != nil {
+			return
+		}
+		in := buf[:n]
+		for len(in) > 0 {
+			switch {
+			case len(in) >= 3 && in[0] == 0x1b && in[1] == '[':
+				switch in[2] {
+				case 'C', 'A':
+					keys <- "right"
+				case 'D', 'B':
+					keys <- "left"
+				}
+				in = in[3:]
+				continue
+			case in[0] == 3 || in[0] == 'q' || in[0] == 'Q': // Ctrl-C
+				keys <- "q"
+			case in[0] == '+' || in[0] == '=':
+				keys <- "+"
+			case in[0] == '-' || in[0] == '_':
+				keys <- "-"
+			case in[0] == ' ':
+				keys <- " "
+			}
+			in = in[1:]
+		}
+	}
+}
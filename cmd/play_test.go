@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	lib "lem-in/helpers"
+)
+
+func TestPlayDrawDeterministic(t *testing.T) {
+	// соседние комнаты в окне 80×24 сливаются, подписи накладываются друг на друга
+	var rooms []lib.Room
+	links := map[string][]string{}
+	for i, name := range []string{"s", "aa", "bb", "cc", "dd", "ee", "e"} {
+		rooms = append(rooms, lib.Room{Name: name, X: i, Y: 0})
+		if i > 0 {
+			prev := rooms[i-1].Name
+			links[prev] = append(links[prev], name)
+			links[name] = append(links[name], prev)
+		}
+	}
+	rooms = append(rooms, lib.Room{Name: "far", X: 1000, Y: 1000})
+	p := &player{g: links, n: 1, start: "s", end: "e", rooms: rooms, turns: [][]string{{"L1-aa"}}, turn: 1}
+
+	// не терминал: stty не срабатывает, размер окна — 80×24 по умолчанию
+	var first string
+	for i := 0; i < 20; i++ {
+		name := filepath.Join(t.TempDir(), "frame")
+		f, err := os.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.draw(f)
+		f.Close()
+		frame, _ := os.ReadFile(name)
+		if i == 0 {
+			first = string(frame)
+		} else if string(frame) != first {
+			t.Fatalf("frame %d differs from the first one", i)
+		}
+	}
+}
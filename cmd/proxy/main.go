@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"lem-in/graph"
+)
+
+func main() {
+	upstream := flag.String("upstream", graph.DefaultUpstream, "graph editor URL to proxy")
+	addr := flag.String("addr", "127.0.0.1:8080", "listen address")
+	flag.Parse()
+
+	u, err := url.Parse(*upstream)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		log.Fatalf("invalid upstream URL: %q", *upstream)
+	}
+
+	fmt.Printf("server running {\033[38;2;255;0;128mhttp://%s\033[m} -> %s\n", *addr, u)
+	log.Fatal(http.ListenAndServe(*addr, graph.Handler(u)))
+}
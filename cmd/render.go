@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	lib "lem-in/helpers"
+)
+
+// buildView — парсит карту, решает её выбранной стратегией и собирает данные
+// для отрисовки; heat — добавить загрузку комнат и связей
+func buildView(fileName, solverName string, heat bool) *lib.FarmView {
+	farm := parseG(fileName, lib.ParseOptions{})
+	plan, err := solveFarm(context.Background(), farm, solverName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return lib.NewFarmView(farm, plan, heat)
+}
+
+// runRender — "lem-in render -turn=k -out=frame.svg map.txt"
+func runRender(args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	turn := fs.Int("turn", 0, "turn to render (0 — before the first move)")
+	out := fs.String("out", "", "output file (default stdout)")
+	animate := fs.Bool("animate", false, "render the whole run as an animated GIF")
+	frames := fs.Int("frames", 0, "in-between frames per turn for -animate")
+	delay := fs.Duration("delay", 700*time.Millisecond, "time per turn for -animate")
+	heat := fs.Bool("heat", false, "colour rooms and links by utilisation")
+	dot := fs.Bool("dot", false, "export the farm as Graphviz DOT instead of SVG")
+	solverName := fs.String("solver", lib.DefaultSolver, "path-finding strategy")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: lem-in render [-solver name] [-turn=k] [-heat] [-out=frame.svg] map.txt")
+		fmt.Println("       lem-in render -animate [-frames=n] [-delay=700ms] -out=run.gif map.txt")
+		fmt.Println("       lem-in render -dot [-heat] [-out=farm.dot] map.txt")
+		os.Exit(1)
+	}
+
+	v := buildView(fs.Arg(0), *solverName, *heat)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	var err error
+	switch {
+	case *dot:
+		err = lib.RenderDOT(w, v)
+	case *animate:
+		err = lib.RenderGIF(w, v, max(*frames, 0), int(*delay/(10*time.Millisecond)))
+	default:
+		err = lib.RenderSVG(w, v, *turn)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	lib "lem-in/helpers"
+	"lem-in/web"
+)
+
+// те же поля, что отдаёт /data сервера — script.js читает именно их
+type reportRoom struct {
+	Name    string   `json:"name"`
+	X       int      `json:"x"`
+	Y       int      `json:"y"`
+	IsStart bool     `json:"isStart"`
+	IsEnd   bool     `json:"isEnd"`
+	Links   []string `json:"links"`
+}
+
+type reportData struct {
+	Rooms []reportRoom `json:"rooms"`
+	Moves []string     `json:"moves"`
+}
+
+type reportPath struct {
+	Rooms string
+	Len   int
+	Ants  int
+}
+
+type reportPage struct {
+	Name     string
+	Map      string
+	Ants     int
+	Rooms    int
+	Links    int
+	Turns    int
+	Paths    []reportPath
+	Data     reportData
+	AntImage string
+	CSS      template.CSS
+	JS       template.JS
+}
+
+var reportTmpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="UTF-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>Lem-in report: {{.Name}}</title>
+  <style>{{.CSS}}</style>
+  <style>
+    .report { position: absolute; top: 10px; left: 10px; z-index: 10; max-width: 40%; font-size: 13px;
+      background: rgba(255,255,255,.9); border: 1px solid #ccc; border-radius: 5px; padding: 6px 10px; }
+    .report table { border-collapse: collapse; }
+    .report td, .report th { padding: 2px 6px; text-align: left; vertical-align: top; }
+    .report pre { max-height: 300px; overflow: auto; }
+  </style>
+</head>
+<body>
+  <canvas id="canvas"></canvas>
+
+  <div class="step-counter" id="stepCounter">Step: 0</div>
+
+  <details class="report" open>
+    <summary><b>{{.Name}}</b></summary>
+    <table>
+      <tr><th>Муравьи</th><td>{{.Ants}}</td></tr>
+      <tr><th>Комнаты</th><td>{{.Rooms}}</td></tr>
+      <tr><th>Связи</th><td>{{.Links}}</td></tr>
+      <tr><th>Ходы</th><td>{{.Turns}}</td></tr>
+    </table>
+    <table>
+      <tr><th>#</th><th>Путь</th><th>Длина</th><th>Муравьи</th></tr>
+      {{range $i, $p := .Paths}}<tr><td>{{$i}}</td><td>{{$p.Rooms}}</td><td>{{$p.Len}}</td><td>{{$p.Ants}}</td></tr>
+      {{end}}
+    </table>
+    <details><summary>Карта</summary><pre>{{.Map}}</pre></details>
+  </details>
+
+  <div class="controls">
+    <button id="startBtn">Старт</button>
+    <button id="pauseBtn">Пауза</button>
+    <button id="resetBtn">Сброс</button>
+  </div>
+
+  <script>
+    window.LEMIN_DATA = {{.Data}};
+    window.LEMIN_ANT_IMAGE = {{.AntImage}};
+  </script>
+  <script>{{.JS}}</script>
+</body>
+</html>
+`))
+
+// runReport — "lem-in report map.txt -o report.html": один HTML-файл с картой,
+// решением, статистикой и визуализатором, работает без сервера.
+func runReport(args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	out := fs.String("o", "report.html", "output HTML file")
+	solverName := fs.String("solver", lib.DefaultSolver, "path-finding strategy")
+	// флаги допускаются и после имени файла: report map.txt -o out.html
+	var fileName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		fileName, args = args[0], args[1:]
+	}
+	fs.Parse(args)
+	if fileName == "" && fs.NArg() == 1 {
+		fileName = fs.Arg(0)
+	}
+	if fileName == "" {
+		fmt.Println("Usage: lem-in report map.txt [-o report.html] [-solver name]")
+		os.Exit(1)
+	}
+
+	mapText, err := readMap(fileName)
+	if err != nil {
+		fmt.Println("Ошибка чтения файла:", err)
+		os.Exit(1)
+	}
+	v := buildView(fileName, *solverName, false)
+
+	page := reportPage{
+		Name:  filepath.Base(fileName),
+		Map:   strings.ReplaceAll(string(mapText), "\r\n", "\n"),
+		Ants:  v.Ants,
+		Rooms: len(v.Rooms),
+		Links: len(v.Links),
+		Turns: len(v.Turns),
+	}
+
+	links := map[string][]string{}
+	for _, l := range v.Links {
+		links[l[0]] = append(links[l[0]], l[1])
+		links[l[1]] = append(links[l[1]], l[0])
+	}
+	for _, r := range v.Rooms {
+		page.Data.Rooms = append(page.Data.Rooms, reportRoom{
+			Name: r.Name, X: r.X, Y: r.Y,
+			IsStart: r.Name == v.Start, IsEnd: r.Name == v.End,
+			Links: append([]string{}, links[r.Name]...),
+		})
+	}
+	for _, turn := range v.Turns {
+		page.Data.Moves = append(page.Data.Moves, strings.Join(turn, " "))
+	}
+
+	for i, p := range v.Paths {
+		page.Paths = append(page.Paths, reportPath{
+			Rooms: strings.Join(append([]string{v.Start}, p...), " → "),
+			Len:   len(p),
+			Ants:  v.PathAnts[i],
+		})
+	}
+
+	css, err1 := web.FS.ReadFile("style.css")
+	js, err2 := web.FS.ReadFile("script.js")
+	img, err3 := web.FS.ReadFile("image/ANTS.svg")
+	if err1 != nil || err2 != nil || err3 != nil {
+		fmt.Println("embedded web assets are missing")
+		os.Exit(1)
+	}
+	page.CSS = template.CSS(css)
+	page.JS = template.JS(js)
+	page.AntImage = "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(img)
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := reportTmpl.Execute(f, page); err != nil {
+		f.Close()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
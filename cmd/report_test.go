@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lib "lem-in/helpers"
+)
+
+func TestReport(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "report.html")
+	for _, solver := range []string{"dfs", "exact"} {
+		runReport([]string{"../examples/example01.txt", "-o", out, "-solver", solver})
+		raw, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		page := string(raw)
+
+		// отчёт открывается без сети: ни внешних скриптов, ни картинок, ни стилей
+		for _, ref := range []string{`src="http`, `href="http`, `<script src=`, `<link `} {
+			if strings.Contains(page, ref) {
+				t.Errorf("%s: external reference %q", solver, ref)
+			}
+		}
+		if !strings.Contains(page, "data:image/svg+xml;base64,") {
+			t.Errorf("%s: ant image is not inlined", solver)
+		}
+
+		_, after, ok := strings.Cut(page, "window.LEMIN_DATA = ")
+		js, _, ok2 := strings.Cut(after, ";\n")
+		if !ok || !ok2 {
+			t.Fatalf("%s: no embedded solution", solver)
+		}
+		var data reportData
+		if err := json.Unmarshal([]byte(js), &data); err != nil {
+			t.Fatalf("%s: embedded solution: %v", solver, err)
+		}
+		farm := parseG("../examples/example01.txt", lib.ParseOptions{})
+		if len(data.Rooms) != len(farm.Rooms) || len(data.Moves) == 0 {
+			t.Fatalf("%s: %d rooms, %d moves", solver, len(data.Rooms), len(data.Moves))
+		}
+		moves := make([][]string, len(data.Moves))
+		for i, m := range data.Moves {
+			moves[i] = strings.Fields(m)
+		}
+		if _, err := lib.CheckMoves(farm, moves); err != nil {
+			t.Errorf("%s: embedded moves: %v", solver, err)
+		}
+	}
+}
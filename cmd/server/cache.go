@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	lib "lem-in/helpers"
+)
+
+// keyHash — потоковый нормализатор карты для mapKey: io.Writer, который хеширует
+// строки без \r, пустых строк, пробелов по краям и обычных комментариев
+// (директивы ## остаются). В памяти — только незаконченная строка.
+type keyHash struct {
+	h    hash.Hash
+	line []byte
+}
+
+func newKeyHash() *keyHash { return &keyHash{h: sha256.New()} }
+
+func (k *keyHash) Write(p []byte) (int, error) {
+	n := len(p)
+	for {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			k.line = append(k.line, p...)
+			return n, nil
+		}
+		if len(k.line) == 0 {
+			k.hashLine(p[:i])
+		} else {
+			k.line = append(k.line, p[:i]...)
+			k.hashLine(k.line)
+			k.line = k.line[:0]
+		}
+		p = p[i+1:]
+	}
+}
+
+func (k *keyHash) hashLine(raw []byte) {
+	line := bytes.TrimSpace(raw)
+	if len(line) == 0 || (line[0] == '#' && !bytes.HasPrefix(line, []byte("##"))) {
+		return
+	}
+	k.h.Write(line)
+	k.h.Write([]byte{'\n'})
+}
+
+// Sum — ключ карты; последняя строка может быть без \n
+func (k *keyHash) Sum() string {
+	k.hashLine(k.line)
+	k.line = k.line[:0]
+	return hex.EncodeToString(k.h.Sum(nil))
+}
+
+// mapKey — SHA-256 нормализованного текста карты, читается потоково
+func mapKey(r io.Reader) (string, error) {
+	k := newKeyHash()
+	if _, err := io.Copy(k, r); err != nil {
+		return "", err
+	}
+	return k.Sum(), nil
+}
+
+// mapSource — откуда читать карту; каждый вызов открывает её заново с начала,
+// так что ни задача, ни кеш не держат текст карты в памяти
+type mapSource func() (io.ReadCloser, error)
+
+func fileSource(path string) mapSource {
+	return func() (io.ReadCloser, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+		return f, nil
+	}
+}
+
+// textSource — карта, пришедшая в теле запроса
+func textSource(text string) mapSource {
+	return func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(text)), nil }
+}
+
+// sourceKey — mapKey карты из источника
+func sourceKey(src mapSource) (string, error) {
+	r, err := src()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	return mapKey(r)
+}
+
+// dataSchema — версия формата ответа /data (dataJSON). Входит в ключ кеша и ETag,
+// поэтому при изменении формата старые ответы на диске и у клиентов не используются.
+const dataSchema = "v2"
+
+// solutionKey — ключ решения: версия формата и хеш карты, для нестандартной
+// стратегии — с её именем
+func solutionKey(mapKey, solver string) string {
+	key := dataSchema + "-" + mapKey
+	if solver == "" || solver == lib.DefaultSolver {
+		return key
+	}
+	return key + "-" + solver
+}
+
+type cacheEntry struct {
+	key  string
+	body []byte
+}
+
+// solutionCache — LRU готовых ответов /data; при заданном dir записи
+// дублируются на диск и переживают перезапуск сервера.
+type solutionCache struct {
+	mu    sync.Mutex
+	size  int
+	dir   string
+	order *list.List
+	items map[string]*list.Element
+}
+
+func newSolutionCache(size int, dir string) *solutionCache {
+	return &solutionCache{
+		size:  size,
+		dir:   dir,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *solutionCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		body := el.Value.(*cacheEntry).body
+		c.mu.Unlock()
+		return body, true
+	}
+	c.mu.Unlock()
+
+	if c.dir == "" {
+		return nil, false
+	}
+	body, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+	c.put(key, body)
+	return body, true
+}
+
+func (c *solutionCache) Put(key string, body []byte) {
+	c.put(key, body)
+	if c.dir != "" {
+		// ошибки записи на диск не критичны — останется кеш в памяти
+		if err := os.MkdirAll(c.dir, 0o755); err == nil {
+			tmp := filepath.Join(c.dir, key+".json.tmp")
+			if os.WriteFile(tmp, body, 0o644) == nil {
+				os.Rename(tmp, filepath.Join(c.dir, key+".json"))
+			}
+		}
+	}
+}
+
+func (c *solutionCache) put(key string, body []byte) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*cacheEntry).body = body
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, body: body})
+	for c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.items, last.Value.(*cacheEntry).key)
+	}
+}
+
+// etagMatch — совпадает ли ETag с заголовком If-None-Match (список или "*")
+func etagMatch(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMapKey(t *testing.T) {
+	a, err := mapKey(strings.NewReader(testMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// \r, пустые строки, пробелы и комментарии ключ не меняют, директивы — меняют
+	same := strings.ReplaceAll("  "+testMap, "\n", "\r\n\n#note\n")
+	if b, _ := mapKey(strings.NewReader(same)); b != a {
+		t.Fatalf("normalised map has another key")
+	}
+	if b, _ := mapKey(strings.NewReader(strings.Replace(testMap, "##end", "##start", 1))); b == a {
+		t.Fatalf("directive change kept the key")
+	}
+	if !strings.HasPrefix(solutionKey(a, "flow"), dataSchema+"-"+a) {
+		t.Fatalf("solutionKey = %s", solutionKey(a, "flow"))
+	}
+}
+
+func TestDataETag(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "map.txt")
+	if err := os.WriteFile(file, []byte(testMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache := newSolutionCache(4, "")
+	srv := httptest.NewServer(handleData(file, cache, newJobQueue(1, 1, cache, 0)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(etag, `"`+dataSchema+"-") {
+		t.Fatalf("status %d, ETag %q", resp.StatusCode, etag)
+	}
+
+	for header, want := range map[string]int{
+		etag:               http.StatusNotModified,
+		"W/" + etag:        http.StatusNotModified,
+		`"other", ` + etag: http.StatusNotModified,
+		`"other"`:          http.StatusOK,
+	} {
+		req, _ := http.NewRequest("GET", srv.URL, nil)
+		req.Header.Set("If-None-Match", header)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want || resp.Header.Get("ETag") != etag {
+			t.Errorf("If-None-Match %s: status %d, ETag %q", header, resp.StatusCode, resp.Header.Get("ETag"))
+		}
+	}
+}
+
+func TestSolutionCacheLRU(t *testing.T) {
+	c := newSolutionCache(2, "")
+	c.Put("a", []byte("A"))
+	c.Put("b", []byte("B"))
+	c.Get("a") // b становится самой старой записью
+	c.Put("c", []byte("C"))
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("least recently used entry not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Fatalf("%s evicted", key)
+		}
+	}
+
+	// записи на диске переживают вытеснение из памяти
+	c = newSolutionCache(1, t.TempDir())
+	c.Put("a", []byte("A"))
+	c.Put("b", []byte("B"))
+	if body, ok := c.Get("a"); !ok || string(body) != "A" {
+		t.Fatalf("disk entry: %q, %v", body, ok)
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lib "lem-in/helpers"
+)
+
+// jobTTL — сколько хранить завершённые задачи для GET /jobs/{id}
+const jobTTL = 10 * time.Minute
+
+var errQueueFull = errors.New("solve queue is full, try again later")
+
+const (
+	jobQueued  = "queued"
+	jobRunning = "running"
+	jobDone    = "done"
+	jobFailed  = "failed"
+)
+
+// solveProgress — ход решения (lib.SolveProgress), обновляется из parseFarm и стратегий через контекст
+type solveProgress struct {
+	stage atomic.Value
+	lines atomic.Int64
+	paths atomic.Int64
+}
+
+func withProgress(ctx context.Context, p *solveProgress) context.Context {
+	return lib.WithSolveProgress(ctx, p)
+}
+
+func progressFrom(ctx context.Context) *solveProgress {
+	p, _ := lib.SolveProgressFrom(ctx).(*solveProgress)
+	return p
+}
+
+func (p *solveProgress) SetStage(stage string) {
+	if p != nil {
+		p.stage.Store(stage)
+	}
+}
+
+func (p *solveProgress) AddPath() {
+	if p != nil {
+		p.paths.Add(1)
+	}
+}
+
+// job — одно решение карты в очереди
+type job struct {
+	id     string
+	key    string
+	src    mapSource
+	solver string
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	progress solveProgress
+
+	mu         sync.Mutex
+	status     string
+	queuedAt   time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+	body       []byte
+	err        error
+}
+
+// newJob — задача для карты из src; key — solutionKey этой карты и стратегии
+func newJob(parent context.Context, key string, src mapSource, solver string) *job {
+	ctx, cancel := context.WithCancel(parent)
+	id := make([]byte, 8)
+	rand.Read(id)
+	return &job{
+		id:       hex.EncodeToString(id),
+		key:      key,
+		src:      src,
+		solver:   solver,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		status:   jobQueued,
+		queuedAt: time.Now(),
+	}
+}
+
+func (j *job) finish(body []byte, err error) {
+	j.mu.Lock()
+	j.body, j.err = body, err
+	j.finishedAt = time.Now()
+	j.status = jobDone
+	if err != nil {
+		j.status = jobFailed
+	}
+	j.mu.Unlock()
+	j.cancel()
+	close(j.done)
+}
+
+// jobQueue — ограниченная очередь и фиксированное число воркеров для решений
+type jobQueue struct {
+	queue   chan *job
+	cache   *solutionCache
+	timeout time.Duration
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+func newJobQueue(workers, size int, cache *solutionCache, timeout time.Duration) *jobQueue {
+	q := &jobQueue{
+		queue:   make(chan *job, max(size, 0)),
+		cache:   cache,
+		timeout: timeout,
+		jobs:    make(map[string]*job),
+	}
+	for i := 0; i < max(workers, 1); i++ {
+		go q.worker()
+	}
+	return q
+}
+
+// submit — ставит задачу в очередь; если очередь заполнена — errQueueFull
+func (q *jobQueue) submit(j *job) error {
+	if body, ok := q.cache.Get(j.key); ok {
+		j.finish(body, nil)
+		return nil
+	}
+	select {
+	case q.queue <- j:
+		return nil
+	default:
+		j.cancel()
+		return errQueueFull
+	}
+}
+
+func (q *jobQueue) worker() {
+	for j := range q.queue {
+		q.run(j)
+	}
+}
+
+func (q *jobQueue) run(j *job) {
+	if err := j.ctx.Err(); err != nil {
+		j.finish(nil, err)
+		return
+	}
+	j.mu.Lock()
+	j.status = jobRunning
+	j.startedAt = time.Now()
+	j.mu.Unlock()
+
+	ctx, cancel := withSolveTimeout(j.ctx, q.timeout)
+	defer cancel()
+	r, err := j.src()
+	if err != nil {
+		j.finish(nil, err)
+		return
+	}
+	resp, err := solveData(withProgress(ctx, &j.progress), r, j.solver)
+	r.Close()
+	if err != nil {
+		j.finish(nil, err)
+		return
+	}
+	body := encodeData(resp)
+	q.cache.Put(j.key, body)
+	j.finish(body, nil)
+}
+
+// register — задача будет доступна через GET /jobs/{id}; заодно чистим старые
+func (q *jobQueue) register(j *job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	for id, old := range q.jobs {
+		old.mu.Lock()
+		expired := !old.finishedAt.IsZero() && now.Sub(old.finishedAt) > jobTTL
+		old.mu.Unlock()
+		if expired {
+			delete(q.jobs, id)
+		}
+	}
+	q.jobs[j.id] = j
+}
+
+func (q *jobQueue) get(id string) (*job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	return j, ok
+}
+
+type jobJSON struct {
+	ID         string          `json:"id"`
+	Solver     string          `json:"solver"`
+	Status     string          `json:"status"`
+	Stage      string          `json:"stage,omitempty"`
+	Lines      int64           `json:"linesParsed"`
+	PathsFound int64           `json:"pathsFound"`
+	QueuedAt   time.Time       `json:"queuedAt"`
+	StartedAt  *time.Time      `json:"startedAt,omitempty"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"`
+}
+
+func (j *job) toJSON() jobJSON {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	v := jobJSON{
+		ID:         j.id,
+		Solver:     j.solver,
+		Status:     j.status,
+		Lines:      j.progress.lines.Load(),
+		PathsFound: j.progress.paths.Load(),
+		QueuedAt:   j.queuedAt,
+		Result:     j.body,
+	}
+	if stage, ok := j.progress.stage.Load().(string); ok && j.status == jobRunning {
+		v.Stage = stage
+	}
+	if !j.startedAt.IsZero() {
+		v.StartedAt = &j.startedAt
+	}
+	if !j.finishedAt.IsZero() {
+		v.FinishedAt = &j.finishedAt
+	}
+	if j.err != nil {
+		v.Error = j.err.Error()
+	}
+	return v
+}
+
+func writeJob(w http.ResponseWriter, status int, j *job) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(j.toJSON())
+}
+
+type jobRequest struct {
+	File   string `json:"file"`
+	Map    string `json:"map"`
+	Solver string `json:"solver"`
+}
+
+// POST /jobs — ставит решение в очередь: {"file": "..."}, {"map": "<текст>"} или ?file=;
+// стратегия — "solver" в JSON или ?solver=
+func (q *jobQueue) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	req := jobRequest{File: r.URL.Query().Get("file"), Solver: r.URL.Query().Get("solver")}
+	if req.File == "" {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 10<<20)).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Solver == "" {
+		req.Solver = lib.DefaultSolver
+	}
+	if _, err := lib.LookupSolver(req.Solver); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var src mapSource
+	switch {
+	case req.Map != "":
+		src = textSource(req.Map)
+	case req.File != "":
+		src = fileSource(resolveFile(req.File, ""))
+	default:
+		http.Error(w, "either file or map is required", http.StatusBadRequest)
+		return
+	}
+	key, err := sourceKey(src)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// задача живёт дольше запроса, поэтому её контекст не связан с r.Context()
+	j := newJob(context.Background(), solutionKey(key, req.Solver), src, req.Solver)
+	if err := q.submit(j); err != nil {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	q.register(j)
+	w.Header().Set("Location", "/jobs/"+j.id)
+	writeJob(w, http.StatusAccepted, j)
+}
+
+// GET /jobs/{id} — статус, ход решения и результат
+func (q *jobQueue) handleGet(w http.ResponseWriter, r *http.Request) {
+	j, ok := q.get(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	writeJob(w, http.StatusOK, j)
+}
+
+// solve — решение в пуле для синхронного /data: ждёт результат, а при
+// отключении клиента отменяет задачу, даже если она ещё в очереди.
+func (q *jobQueue) solve(ctx context.Context, key string, src mapSource, solver string) ([]byte, error) {
+	j := newJob(ctx, key, src, solver)
+	if err := q.submit(j); err != nil {
+		return nil, err
+	}
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		j.cancel()
+		return nil, ctx.Err()
+	}
+	return j.body, j.err
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMap = "3\n##start\na 0 0\n##end\nb 4 0\nc 2 1\na-c\nc-b\na-b\n"
+
+// blockedQueue — очередь с одним воркером и одним местом, воркер занят задачей,
+// которая ждёт закрытия release
+func blockedQueue(t *testing.T) (q *jobQueue, release chan struct{}) {
+	t.Helper()
+	q = newJobQueue(1, 1, newSolutionCache(4, ""), 0)
+	started, release := make(chan struct{}), make(chan struct{})
+	busy := newJob(context.Background(), "busy", func() (io.ReadCloser, error) {
+		close(started)
+		<-release
+		return textSource(testMap)()
+	}, "dfs")
+	if err := q.submit(busy); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+	return q, release
+}
+
+func jobsServer(q *jobQueue) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", q.handleSubmit)
+	mux.HandleFunc("GET /jobs/{id}", q.handleGet)
+	return httptest.NewServer(mux)
+}
+
+func postJob(t *testing.T, srv *httptest.Server, text string) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(jobRequest{Map: text})
+	resp, err := http.Post(srv.URL+"/jobs", "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestSubmitQueueFull(t *testing.T) {
+	q, _ := blockedQueue(t)
+	srv := jobsServer(q)
+	defer srv.Close()
+
+	// первая задача занимает единственное место в очереди, вторая не помещается
+	resp := postJob(t, srv, testMap)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("first job: status %d", resp.StatusCode)
+	}
+	resp = postJob(t, srv, testMap+"#other\nd 1 1\n")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("second job: status %d, want 429", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Fatal("429 without Retry-After")
+	}
+}
+
+func TestSubmitAndPoll(t *testing.T) {
+	q := newJobQueue(1, 1, newSolutionCache(4, ""), 0)
+	srv := jobsServer(q)
+	defer srv.Close()
+
+	resp := postJob(t, srv, testMap)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	loc := resp.Header.Get("Location")
+	if !strings.HasPrefix(loc, "/jobs/") {
+		t.Fatalf("Location = %q", loc)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get(srv.URL + loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v jobJSON
+		err = json.NewDecoder(resp.Body).Decode(&v)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Status == jobDone {
+			var data dataJSON
+			if err := json.Unmarshal(v.Result, &data); err != nil || len(data.Moves) == 0 {
+				t.Fatalf("result %s: %v", v.Result, err)
+			}
+			break
+		}
+		if v.Status == jobFailed || time.Now().After(deadline) {
+			t.Fatalf("job %+v", v)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	resp, err := http.Get(srv.URL + "/jobs/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown job: status %d", resp.StatusCode)
+	}
+}
+
+func TestSolveCancelledWhileQueued(t *testing.T) {
+	ran := make(chan string, 2)
+	src := func(name string) mapSource {
+		return func() (io.ReadCloser, error) {
+			ran <- name
+			return textSource(testMap)()
+		}
+	}
+
+	// синхронное решение отменяется, пока задача ждёт в очереди
+	q, release := blockedQueue(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := q.solve(ctx, "waiting", src("solve"), "dfs")
+		errc <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("solve error = %v, want context.Canceled", err)
+	}
+
+	// отменённая задача из очереди завершается, не начав решения
+	q, release = blockedQueue(t)
+	ctx, cancel = context.WithCancel(context.Background())
+	j := newJob(ctx, "waiting", src("job"), "dfs")
+	if err := q.submit(j); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	close(release)
+	<-j.done
+	if v := j.toJSON(); v.Status != jobFailed || v.StartedAt != nil || v.Error != context.Canceled.Error() {
+		t.Fatalf("cancelled job: %+v", v)
+	}
+	select {
+	case name := <-ran:
+		t.Fatalf("cancelled %s was solved", name)
+	default:
+	}
+}
+
+func TestJobTTL(t *testing.T) {
+	q := newJobQueue(1, 1, newSolutionCache(4, ""), 0)
+	old := newJob(context.Background(), "old", textSource(testMap), "dfs")
+	old.finish(nil, nil)
+	old.finishedAt = time.Now().Add(-jobTTL - time.Second)
+	fresh := newJob(context.Background(), "fresh", textSource(testMap), "dfs")
+	fresh.finish(nil, nil)
+	q.register(old)
+	q.register(fresh)
+	if _, ok := q.get(old.id); ok {
+		t.Fatal("expired job still registered")
+	}
+	if _, ok := q.get(fresh.id); !ok {
+		t.Fatal("fresh job evicted")
+	}
+}
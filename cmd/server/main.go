@@ -1,380 +1,191 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
+	"runtime"
 	"strconv"
 	"strings"
-)
-
-// Graph — карта: имя комнаты -> список соседей
-type Graph map[string][]string
+	"time"
 
-type roomCoord struct {
-	X int
-	Y int
-}
-
-func (g Graph) addRoom(name string) {
-	if _, exists := g[name]; !exists {
-		g[name] = []string{}
-	}
-}
-
-func (g Graph) addLink(from, to string) {
-	g[from] = append(g[from], to)
-	g[to] = append(g[to], from)
-}
+	lib "lem-in/helpers"
+	"lem-in/web"
+)
 
-// parseG — читает и валидирует файл, возвращает граф, n, start, end и координаты
-func parseG(fileName string) (Graph, int, string, string, map[string]roomCoord, error) {
-	g := Graph{}
-	coords := make(map[string]roomCoord)
+// limits — ограничения парсера из флагов -max-rooms, -max-links, -max-line
+var limits lib.ParseOptions
 
-	data, err := os.ReadFile(fileName)
+// parseG — потоково читает и валидирует файл, возвращает новую ферму (без решения)
+func parseG(ctx context.Context, fileName string) (*lib.Farm, error) {
+	file, err := os.Open(fileName)
 	if err != nil {
-		return nil, 0, "", "", nil, fmt.Errorf("read file: %w", err)
-	}
-	text := strings.ReplaceAll(string(data), "\r\n", "\n")
-	lines := strings.Split(text, "\n")
-	if len(lines) == 0 {
-		return nil, 0, "", "", nil, fmt.Errorf("empty file")
-	}
-
-	n, err := strconv.Atoi(strings.TrimSpace(lines[0]))
-	if err != nil || n <= 0 {
-		return nil, 0, "", "", nil, fmt.Errorf("invalid ants count in first line")
-	}
-
-	start, end := "", ""
-	flagMode := "room"
-
-	for _, raw := range lines[1:] {
-		line := strings.TrimSpace(raw)
-		if line == "" {
-			continue
-		}
-		if strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "##") {
-			continue
-		}
-		if strings.HasPrefix(line, "##") {
-			switch strings.TrimSpace(line) {
-			case "##start":
-				flagMode = "start"
-			case "##end":
-				flagMode = "end"
-			}
-			continue
-		}
-
-		parts := strings.Fields(line)
-		if len(parts) == 3 { // room line
-			roomName := parts[0]
-			x, errX := strconv.Atoi(parts[1])
-			y, errY := strconv.Atoi(parts[2])
-			if roomName == "" || errX != nil || errY != nil {
-				return nil, 0, "", "", nil, fmt.Errorf("invalid room line: %s", line)
-			}
-			g.addRoom(roomName)
-			coords[roomName] = roomCoord{X: x, Y: y}
-			if flagMode == "start" {
-				start = roomName
-				flagMode = "room"
-			} else if flagMode == "end" {
-				end = roomName
-				flagMode = "room"
-			}
-			continue
-		}
-
-		if strings.Contains(line, "-") && !strings.Contains(line, " ") { // link line
-			linkParts := strings.Split(line, "-")
-			if len(linkParts) != 2 {
-				return nil, 0, "", "", nil, fmt.Errorf("invalid link: %s", line)
-			}
-			a := strings.TrimSpace(linkParts[0])
-			b := strings.TrimSpace(linkParts[1])
-			if a == b || a == "" || b == "" {
-				return nil, 0, "", "", nil, fmt.Errorf("invalid link endpoints: %s", line)
-			}
-			if _, ok := g[a]; !ok {
-				return nil, 0, "", "", nil, fmt.Errorf("link references undefined room: %s", a)
-			}
-			if _, ok := g[b]; !ok {
-				return nil, 0, "", "", nil, fmt.Errorf("link references undefined room: %s", b)
-			}
-			// prevent duplicates (simple check)
-			exists := false
-			for _, v := range g[a] {
-				if v == b {
-					exists = true
-					break
-				}
-			}
-			if exists {
-				return nil, 0, "", "", nil, fmt.Errorf("duplicate link: %s-%s", a, b)
-			}
-			g.addLink(a, b)
-			continue
-		}
-
-		return nil, 0, "", "", nil, fmt.Errorf("wrong format near: %s", line)
+		return nil, fmt.Errorf("read file: %w", err)
 	}
+	defer file.Close()
+	return parseFarm(ctx, file)
+}
 
-	if start == "" || end == "" {
-		return nil, 0, "", "", nil, fmt.Errorf("start or end not defined")
-	}
-	return g, n, start, end, coords, nil
+// parseText — то же, что parseG, но для уже прочитанного текста карты
+func parseText(ctx context.Context, data string) (*lib.Farm, error) {
+	return parseFarm(ctx, strings.NewReader(data))
 }
 
-// getBestGroup — все простые пути start->end
-func (g Graph) getBestGroup(start, end string) [][]string {
-	ans := [][]string{}
-	path := []string{start}
-	visited := map[string]bool{start: true}
-	var dfs func(string)
-	dfs = func(cur string) {
-		if cur == end {
-			cp := append([]string{}, path...)
-			ans = append(ans, cp)
-			return
-		}
-		for _, nb := range g[cur] {
-			if !visited[nb] {
-				visited[nb] = true
-				path = append(path, nb)
-				dfs(nb)
-				path = path[:len(path)-1]
-				delete(visited, nb)
-			}
-		}
+// parseFarm — разбор с ограничениями сервера; число строк попадает в ход решения задачи
+func parseFarm(ctx context.Context, r io.Reader) (*lib.Farm, error) {
+	opts := limits
+	if p := progressFrom(ctx); p != nil {
+		opts.Progress = func(s lib.ParseStats) { p.lines.Store(int64(s.Lines)) }
 	}
-	dfs(start)
-	return ans
+	return lib.ParseFarm(ctx, r, opts)
 }
 
-func indexOfMin(arr []int) int {
-	if len(arr) == 0 {
-		return -1
+// solveFarm — решает ферму выбранной стратегией и сохраняет группу путей в f.Group
+func solveFarm(ctx context.Context, f *lib.Farm, solverName string) (*lib.Plan, error) {
+	solver, err := lib.LookupSolver(solverName)
+	if err != nil {
+		return nil, err
 	}
-	min := arr[0]
-	idx := 0
-	for i := 1; i < len(arr); i++ {
-		if arr[i] < min {
-			min = arr[i]
-			idx = i
-		}
+	plan, err := solver.Solve(ctx, f)
+	if err != nil {
+		return nil, err
 	}
-	return idx
+	f.Group = plan.Paths
+	return plan, nil
 }
 
-func getCombinedHeights(group [][]string, n int) []int {
-	heights := make([]int, len(group))
-	for i, k := range group {
-		heights[i] = len(k)
+// generateMoves — решает ферму и формирует шаги вида "L1-roomA L2-roomB"
+func generateMoves(ctx context.Context, f *lib.Farm, solverName string) ([]string, error) {
+	plan, err := solveFarm(ctx, f, solverName)
+	if err != nil {
+		return nil, err
 	}
-	for i := 0; i < n; i++ {
-		midIndex := indexOfMin(heights)
-		if midIndex >= 0 {
-			heights[midIndex]++
-		}
+	moves := lib.PlanMoves(f, plan)
+	steps := make([]string, len(moves))
+	for i, turn := range moves {
+		steps[i] = strings.Join(turn, " ")
 	}
-	return heights
+	return steps, nil
 }
 
-func getHeight(group [][]string, n int) (int, []int) {
-	heights := getCombinedHeights(group, n)
-	if len(heights) == 0 {
-		return 0, nil
-	}
-	return heights[0], heights
+type roomJSON struct {
+	Name    string   `json:"name"`
+	X       int      `json:"x"`
+	Y       int      `json:"y"`
+	IsStart bool     `json:"isStart"`
+	IsEnd   bool     `json:"isEnd"`
+	Links   []string `json:"links"`
 }
 
-func bestGroupByHeight(groups [][][]string, n int) [][]string {
-	heights := make([]int, len(groups))
-	for i, group := range groups {
-		heights[i], _ = getHeight(group, n)
-	}
-	minIndex := indexOfMin(heights)
-	if minIndex == -1 {
-		return [][]string{}
-	}
-	return groups[minIndex]
+// dataJSON — ответ /data; при изменении полей увеличьте dataSchema
+type dataJSON struct {
+	Rooms       []roomJSON       `json:"rooms"`
+	Moves       []string         `json:"moves"`
+	Utilisation *lib.Utilisation `json:"utilisation"`
 }
 
-func (g Graph) sendTheAnts(n int, start, end string) [][]string {
-	paths := g.getBestGroup(start, end)
-	if len(paths) == 0 {
-		return [][]string{}
-	}
-	sort.Slice(paths, func(i, j int) bool {
-		if len(paths[i]) != len(paths[j]) {
-			return len(paths[i]) < len(paths[j])
-		}
-		return strings.Join(paths[i], ",") < strings.Join(paths[j], ",")
-	})
-	for i, p := range paths {
-		if len(p) >= 2 {
-			paths[i] = p[1 : len(p)-1]
-		} else {
-			paths[i] = []string{}
-		}
-	}
-	for i := 0; i < len(paths); i++ {
-		for ii := i + 1; ii < len(paths); ii++ {
-			if len(paths[ii-1]) > len(paths[ii]) {
-				paths[ii-1], paths[ii] = paths[ii], paths[ii-1]
-			}
-		}
-	}
-	ans := [][][]string{}
-	for i := 0; i < len(paths); i++ {
-		path2 := [][]string{paths[i]}
-		for ii := i + 1; ii < len(paths); ii++ {
-			conflict := false
-			for _, s1 := range paths[ii] {
-				for _, arr1 := range path2 {
-					for _, s2 := range arr1 {
-						if s1 == s2 {
-							conflict = true
-							break
-						}
-					}
-					if conflict {
-						break
-					}
-				}
-				if conflict {
-					break
-				}
-			}
-			if !conflict {
-				path2 = append(path2, paths[ii])
-			}
-		}
-		ans = append(ans, path2)
+// solveData — потоковый разбор и решение карты, ответ для /data
+func solveData(ctx context.Context, r io.Reader, solverName string) (*dataJSON, error) {
+	progress := progressFrom(ctx)
+	progress.SetStage("parsing")
+	farm, err := parseFarm(ctx, r)
+	if err != nil {
+		return nil, err
 	}
-	for i, group := range ans {
-		for j, p := range group {
-			ans[i][j] = append(p, end)
+	steps, err := generateMoves(ctx, farm, solverName)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms := make([]roomJSON, 0, len(farm.Rooms))
+	for _, r := range farm.Rooms {
+		rj := roomJSON{
+			Name:    r.Name,
+			X:       r.X,
+			Y:       r.Y,
+			IsStart: r.Name == farm.Start,
+			IsEnd:   r.Name == farm.End,
+			Links:   append([]string{}, farm.Links[r.Name]...),
 		}
+		rooms = append(rooms, rj)
 	}
-	return bestGroupByHeight(ans, n)
+	util := lib.ComputeUtilisation(farm, lib.SplitTurns(steps))
+	return &dataJSON{Rooms: rooms, Moves: steps, Utilisation: util}, nil
 }
 
-// generateMoves — формирует шаги вида "L1-roomA L2-roomB"
-func generateMoves(g Graph, n int, start, end string) []string {
-	bestGroup := g.sendTheAnts(n, start, end)
-	height, combined := getHeight(bestGroup, n)
-	heights := make([]int, len(bestGroup))
-	for i, path := range bestGroup {
-		heights[i] = len(path)
-	}
-	// combined[i] -= heights[i]
-	for i := range combined {
-		combined[i] -= heights[i]
-	}
-
-	counter := 0
-	type Ant struct {
-		Name    string
-		Current int
-		Path    []string
-	}
-	ants := make([]Ant, 0)
-
-	for i := range bestGroup {
-		if n > 0 && i < len(combined) && combined[i] > 0 {
-			counter++
-			ant := Ant{Name: fmt.Sprintf("L%d", counter), Current: 0, Path: bestGroup[i]}
-			ants = append(ants, ant)
-			n--
-			combined[i]--
-		}
-	}
-
-	var steps []string
+func encodeData(d *dataJSON) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(d)
+	return buf.Bytes()
+}
 
-	for height > 1 {
-		height--
-		lineParts := []string{}
-		for i := range ants {
-			lineParts = append(lineParts, fmt.Sprintf("%s-%s", ants[i].Name, ants[i].Path[ants[i].Current]))
-			ants[i].Current++
-		}
-		// remove finished
-		for i := len(ants) - 1; i >= 0; i-- {
-			if ants[i].Current >= len(ants[i].Path) {
-				ants = append(ants[:i], ants[i+1:]...)
-			}
+// handleData — GET /data: решение карты с ETag; повторные решения берутся из кеша
+func handleData(defaultFile string, cache *solutionCache, jobs *jobQueue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		useFile := resolveFile(r.URL.Query().Get("file"), defaultFile)
+		solver := r.URL.Query().Get("solver")
+		if _, err := lib.LookupSolver(solver); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-		for i := 0; i < len(combined) && n > 0; i++ {
-			if combined[i] > 0 {
-				counter++
-				ant := Ant{Name: fmt.Sprintf("L%d", counter), Current: 0, Path: bestGroup[i]}
-				ants = append(ants, ant)
-				n--
-				combined[i]--
-			}
+		src := fileSource(useFile)
+		mk, err := sourceKey(src)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-		steps = append(steps, strings.TrimSpace(strings.Join(lineParts, " ")))
-	}
 
-	for len(ants) > 0 || n > 0 {
-		lineParts := []string{}
-		for i := range ants {
-			if ants[i].Current < len(ants[i].Path) {
-				lineParts = append(lineParts, fmt.Sprintf("%s-%s", ants[i].Name, ants[i].Path[ants[i].Current]))
-				ants[i].Current++
-			}
+		// ответ целиком определяется картой и стратегией, поэтому ETag — их хеш
+		key := solutionKey(mk, solver)
+		etag := `"` + key + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		if etagMatch(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
 		}
-		for i := len(ants) - 1; i >= 0; i-- {
-			if ants[i].Current >= len(ants[i].Path) {
-				ants = append(ants[:i], ants[i+1:]...)
+
+		body, ok := cache.Get(key)
+		if !ok {
+			// решение идёт в общем пуле воркеров; таймаут считается с начала решения
+			body, err = jobs.solve(r.Context(), key, src, solver)
+			if errors.Is(err, errQueueFull) {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
 			}
-		}
-		for i := 0; i < len(combined) && n > 0; i++ {
-			if combined[i] > 0 {
-				counter++
-				ant := Ant{Name: fmt.Sprintf("L%d", counter), Current: 0, Path: bestGroup[i]}
-				ants = append(ants, ant)
-				n--
-				combined[i]--
+			if err != nil {
+				writeSolveError(w, r, err)
+				return
 			}
 		}
-		if len(lineParts) > 0 {
-			steps = append(steps, strings.TrimSpace(strings.Join(lineParts, " ")))
-		} else {
-			break
-		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
 	}
-
-	return steps
-}
-
-type roomJSON struct {
-	Name    string   `json:"name"`
-	X       int      `json:"x"`
-	Y       int      `json:"y"`
-	IsStart bool     `json:"isStart"`
-	IsEnd   bool     `json:"isEnd"`
-	Links   []string `json:"links"`
-}
-
-type dataJSON struct {
-	Rooms []roomJSON `json:"rooms"`
-	Moves []string   `json:"moves"`
 }
 
 func main() {
 	addr := flag.String("addr", ":8080", "listen address")
 	file := flag.String("file", "examples/example05.txt", "input graph file")
-	webDir := flag.String("web", "web", "web directory")
+	webDir := flag.String("web", "", "serve web files from this directory instead of the embedded copy (development)")
+	mapsDir := flag.String("maps", "examples", "directory with editable maps (/maps/{name})")
+	cacheSize := flag.Int("cache-size", 64, "number of solved maps kept in memory (0 disables the cache)")
+	cacheDir := flag.String("cache-dir", "", "also keep solved maps on disk in this directory")
+	solveTimeout := flag.Duration("solve-timeout", 30*time.Second, "maximum time to solve one map (0 — no limit)")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of maps solved concurrently")
+	queueSize := flag.Int("queue", 64, "number of solves waiting for a worker before requests get 429")
+	flag.IntVar(&limits.MaxRooms, "max-rooms", 0, "reject maps with more rooms (0 — no limit)")
+	flag.IntVar(&limits.MaxLinks, "max-links", 0, "reject maps with more links (0 — no limit)")
+	flag.IntVar(&limits.MaxLineLength, "max-line", lib.DefaultMaxLineLength, "maximum map line length in bytes")
 	flag.Parse()
 
 	// Positional args support: server [file] [addr]
@@ -401,29 +212,33 @@ func main() {
 		useFile = filepath.Join(".", useFile)
 	}
 
-	g, n, start, end, _, err := parseG(useFile)
+	// карта читается потоково: разбор и ключ кеша — за один проход
+	startFile, err := os.Open(useFile)
+	if err != nil {
+		fmt.Printf("read file: %v\n", err)
+		os.Exit(1)
+	}
+	startKey := newKeyHash()
+	startCtx, cancelStart := withSolveTimeout(context.Background(), *solveTimeout)
+	startData, err := solveData(startCtx, io.TeeReader(startFile, startKey), lib.DefaultSolver)
+	cancelStart()
+	startFile.Close()
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
+	cache := newSolutionCache(*cacheSize, *cacheDir)
+	cache.Put(solutionKey(startKey.Sum(), lib.DefaultSolver), encodeData(startData))
+	jobs := newJobQueue(*workers, *queueSize, cache, *solveTimeout)
 
 	// Print original file contents like CLI does (first line, then the rest)
-	if dataRaw, err := os.ReadFile(useFile); err == nil {
-		dataStr := strings.ReplaceAll(string(dataRaw), "\r\n", "\n")
-		lines := strings.Split(dataStr, "\n")
-		if len(lines) > 0 && strings.TrimSpace(lines[0]) != "" {
-			fmt.Println(lines[0])
-		}
-		if len(lines) > 1 {
-			fmt.Print(strings.Join(lines[1:], "\n"))
-		}
-		fmt.Println()
-		fmt.Println()
+	if startFile, err = os.Open(useFile); err == nil {
+		lib.EchoMap(os.Stdout, startFile)
+		startFile.Close()
 	}
 
-	// Generate moves and print them line-by-line to stdout, identical formatting
-	steps := generateMoves(g, n, start, end)
-	for _, line := range steps {
+	// Print moves line-by-line to stdout, identical formatting
+	for _, line := range startData.Moves {
 		fmt.Println(line)
 	}
 	fmt.Println()
@@ -431,51 +246,84 @@ func main() {
 	// Print link to visualization for this file
 	fmt.Printf("Open visualization: http://localhost%s/visual.html?file=%s\n", *addr, *file)
 
-	// static files
-	fs := http.FileServer(http.Dir(*webDir))
-	http.Handle("/", fs)
+	static, webSource := staticFiles(*webDir)
+	http.Handle("/", http.FileServer(static))
+
+	http.HandleFunc("/version", handleVersion)
+	http.HandleFunc("GET /solvers", handleSolvers)
+
+	http.HandleFunc("/data", handleData(*file, cache, jobs))
+
+	// асинхронные решения: POST /jobs, затем опрос GET /jobs/{id}
+	http.HandleFunc("POST /jobs", jobs.handleSubmit)
+	http.HandleFunc("GET /jobs/{id}", jobs.handleGet)
+
+	// редактирование карт без внешнего редактора
+	maps := &mapStore{dir: *mapsDir}
+	http.HandleFunc("GET /maps/{name}", maps.handleGet)
+	http.HandleFunc("PUT /maps/{name}", maps.handlePut)
+
+	// GET /render.svg?file=...&turn=k[&heat=1] — снимок фермы на ходу k (с heat — по загрузке)
+	http.HandleFunc("/render.svg", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := withSolveTimeout(r.Context(), *solveTimeout)
+		defer cancel()
+		q := r.URL.Query()
+		heat := q.Get("heat") == "1" || q.Get("heat") == "true"
+		v, err := buildView(ctx, resolveFile(q.Get("file"), *file), q.Get("solver"), heat)
+		if err != nil {
+			writeSolveError(w, r, err)
+			return
+		}
+		turn := 0
+		if q := r.URL.Query().Get("turn"); q != "" {
+			if turn, err = strconv.Atoi(q); err != nil {
+				http.Error(w, "invalid turn parameter", http.StatusBadRequest)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		_ = lib.RenderSVG(w, v, turn)
+	})
 
-	http.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
-		qFile := r.URL.Query().Get("file")
-		useFile := *file
-		if qFile != "" {
-			useFile = qFile
+	// POST /convert — JSON из graph-redactor в тело, в ответ карта lem-in
+	http.HandleFunc("/convert", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
-		// make absolute path if needed
-		if !filepath.IsAbs(useFile) {
-			useFile = filepath.Join(".", useFile)
+		ants := 0
+		if q := r.URL.Query().Get("ants"); q != "" {
+			v, err := strconv.Atoi(q)
+			if err != nil || v <= 0 {
+				http.Error(w, "invalid ants parameter", http.StatusBadRequest)
+				return
+			}
+			ants = v
 		}
-		g, n, start, end, coords, err := parseG(useFile)
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 10<<20))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-
-		steps := generateMoves(g, n, start, end)
-
-		rooms := make([]roomJSON, 0, len(g))
-		for name, links := range g {
-			c := coords[name]
-			rj := roomJSON{
-				Name:    name,
-				X:       c.X,
-				Y:       c.Y,
-				IsStart: name == start,
-				IsEnd:   name == end,
-				Links:   append([]string{}, links...),
-			}
-			rooms = append(rooms, rj)
+		text, err := lib.ConvertRedactor(body, ants)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-
-		resp := dataJSON{Rooms: rooms, Moves: steps}
-		w.Header().Set("Content-Type", "application/json")
-		enc := json.NewEncoder(w)
-		enc.SetIndent("", "  ")
-		_ = enc.Encode(resp)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, text)
 	})
 
-	fmt.Printf("server listening on %s (serving %s)\n", *addr, *webDir)
+	fmt.Printf("server listening on %s (serving %s)\n", *addr, webSource)
 	if err := http.ListenAndServe(*addr, nil); err != nil {
 		panic(err)
 	}
 }
+
+// staticFiles — файлы визуализатора: встроенные в бинарник, либо с диска при -web
+func staticFiles(dir string) (http.FileSystem, string) {
+	if dir != "" {
+		return http.Dir(dir), dir
+	}
+	return http.FS(web.FS), "embedded assets"
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	lib "lem-in/helpers"
+)
+
+// mapOp — одна операция редактирования карты в теле PUT /maps/{name}
+type mapOp struct {
+	Op   string `json:"op"`
+	Name string `json:"name"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Ants int    `json:"ants"`
+}
+
+type mapEdit struct {
+	Ops []mapOp `json:"ops"`
+}
+
+var mapNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// mapStore — карты в каталоге на диске; правки сериализуются мьютексом
+type mapStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func (s *mapStore) path(name string) (string, error) {
+	name = strings.TrimSuffix(name, ".txt")
+	if !mapNameRe.MatchString(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid map name: %q", name)
+	}
+	return filepath.Join(s.dir, name+".txt"), nil
+}
+
+func applyOp(m *lib.MapFile, op mapOp) error {
+	switch op.Op {
+	case "addRoom":
+		return m.AddRoom(op.Name, op.X, op.Y)
+	case "removeRoom":
+		return m.RemoveRoom(op.Name)
+	case "moveRoom":
+		return m.MoveRoom(op.Name, op.X, op.Y)
+	case "addLink":
+		return m.AddLink(op.From, op.To)
+	case "removeLink":
+		return m.RemoveLink(op.From, op.To)
+	case "setStart":
+		return m.SetRole("start", op.Name)
+	case "setEnd":
+		return m.SetRole("end", op.Name)
+	case "setAnts":
+		m.Ants = op.Ants
+		return nil
+	}
+	return fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// GET /maps/{name} — текст карты; 409, если сохранённая карта не разбирается
+func (s *mapStore) handleGet(w http.ResponseWriter, r *http.Request) {
+	p, err := s.path(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		http.Error(w, "map not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// карту, которую нельзя разобрать, нельзя и редактировать — как в PUT
+	if _, err := lib.ParseMapFile(string(data)); err != nil {
+		http.Error(w, "stored map is invalid: "+err.Error(), http.StatusConflict)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+// PUT /maps/{name} — применяет операции к карте (или создаёт новую),
+// проверяет результат парсером и только тогда сохраняет файл.
+func (s *mapStore) handlePut(w http.ResponseWriter, r *http.Request) {
+	p, err := s.path(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var edit mapEdit
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 10<<20)).Decode(&edit); err != nil {
+		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := &lib.MapFile{}
+	created := false
+	data, err := os.ReadFile(p)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		created = true
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	default:
+		if m, err = lib.ParseMapFile(string(data)); err != nil {
+			http.Error(w, "stored map is invalid: "+err.Error(), http.StatusConflict)
+			return
+		}
+	}
+
+	for i, op := range edit.Ops {
+		if err := applyOp(m, op); err != nil {
+			http.Error(w, fmt.Sprintf("op %d (%s): %v", i, op.Op, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	text := m.String()
+	if _, err := parseText(r.Context(), text); err != nil {
+		http.Error(w, "resulting map is invalid: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, []byte(text), 0o644); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		os.Remove(tmp)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	_, _ = w.Write([]byte(text))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mapsServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	store := &mapStore{dir: t.TempDir()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /maps/{name}", store.handleGet)
+	mux.HandleFunc("PUT /maps/{name}", store.handlePut)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, store.dir
+}
+
+func putMap(t *testing.T, url, body string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest("PUT", url, strings.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	text, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(text)
+}
+
+func TestMapNames(t *testing.T) {
+	store := &mapStore{dir: t.TempDir()}
+	for _, name := range []string{"../x", ".hidden", "a/b", ""} {
+		for _, h := range []http.HandlerFunc{store.handleGet, store.handlePut} {
+			r := httptest.NewRequest("PUT", "/maps/x", strings.NewReader(`{"ops": []}`))
+			r.SetPathValue("name", name)
+			w := httptest.NewRecorder()
+			h(w, r)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("name %q: status %d, want 400", name, w.Code)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(store.dir), "x.txt")); err == nil {
+		t.Fatal("file written outside the maps directory")
+	}
+}
+
+func TestMapCreate(t *testing.T) {
+	srv, dir := mapsServer(t)
+	ops := `{"ops": [
+		{"op": "addRoom", "name": "s", "x": 0, "y": 0},
+		{"op": "addRoom", "name": "e", "x": 1, "y": 0},
+		{"op": "addLink", "from": "s", "to": "e"},
+		{"op": "setStart", "name": "s"},
+		{"op": "setEnd", "name": "e"},
+		{"op": "setAnts", "ants": 2}
+	]}`
+	want := "2\n##start\ns 0 0\n##end\ne 1 0\ns-e\n"
+	status, body := putMap(t, srv.URL+"/maps/fresh", ops)
+	if status != http.StatusCreated || body != want {
+		t.Fatalf("status %d, body %q", status, body)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "fresh.txt")); string(data) != want {
+		t.Fatalf("file %q", data)
+	}
+	if status, _ := putMap(t, srv.URL+"/maps/fresh.txt", `{"ops": []}`); status != http.StatusOK {
+		t.Fatalf("edit of existing map: status %d", status)
+	}
+}
+
+func TestMapEditOps(t *testing.T) {
+	srv, dir := mapsServer(t)
+	file := filepath.Join(dir, "farm.txt")
+	tests := []struct {
+		op   string
+		want string
+	}{
+		{`{"op": "addRoom", "name": "d", "x": 5, "y": 5}`, "3\n##start\na 0 0\n##end\nb 4 0\nc 2 1\nd 5 5\na-c\nc-b\na-b\n"},
+		{`{"op": "removeRoom", "name": "c"}`, "3\n##start\na 0 0\n##end\nb 4 0\na-b\n"},
+		{`{"op": "moveRoom", "name": "c", "x": 7, "y": 8}`, "3\n##start\na 0 0\n##end\nb 4 0\nc 7 8\na-c\nc-b\na-b\n"},
+		{`{"op": "addRoom", "name": "d", "x": 5, "y": 5}, {"op": "addLink", "from": "d", "to": "c"}`, "3\n##start\na 0 0\n##end\nb 4 0\nc 2 1\nd 5 5\na-c\nc-b\na-b\nd-c\n"},
+		{`{"op": "removeLink", "from": "b", "to": "a"}`, "3\n##start\na 0 0\n##end\nb 4 0\nc 2 1\na-c\nc-b\n"},
+		{`{"op": "setStart", "name": "c"}`, "3\na 0 0\n##end\nb 4 0\n##start\nc 2 1\na-c\nc-b\na-b\n"},
+		{`{"op": "setEnd", "name": "c"}`, "3\n##start\na 0 0\nb 4 0\n##end\nc 2 1\na-c\nc-b\na-b\n"},
+		{`{"op": "setAnts", "ants": 7}`, "7\n##start\na 0 0\n##end\nb 4 0\nc 2 1\na-c\nc-b\na-b\n"},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(file, []byte(testMap), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		status, body := putMap(t, srv.URL+"/maps/farm", `{"ops": [`+tt.op+`]}`)
+		if status != http.StatusOK || body != tt.want {
+			t.Errorf("%s: status %d\ngot  %q\nwant %q", tt.op, status, body, tt.want)
+			continue
+		}
+		if data, _ := os.ReadFile(file); string(data) != tt.want {
+			t.Errorf("%s: file %q", tt.op, data)
+		}
+	}
+}
+
+func TestMapEditRejected(t *testing.T) {
+	srv, dir := mapsServer(t)
+	file := filepath.Join(dir, "farm.txt")
+	if err := os.WriteFile(file, []byte(testMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, ops := range []string{
+		`{"ops": [{"op": "removeRoom", "name": "a"}]}`, // без start карта не проходит ParseFarm
+		`{"ops": [{"op": "addLink", "from": "a", "to": "z"}]}`,
+		`{"ops": [{"op": "rename"}]}`,
+		`{"ops": `,
+	} {
+		if status, body := putMap(t, srv.URL+"/maps/farm", ops); status != http.StatusBadRequest {
+			t.Errorf("%s: status %d, body %q", ops, status, body)
+		}
+		if data, _ := os.ReadFile(file); string(data) != testMap {
+			t.Fatalf("%s: file changed to %q", ops, data)
+		}
+	}
+}
+
+func TestMapGet(t *testing.T) {
+	srv, dir := mapsServer(t)
+	os.WriteFile(filepath.Join(dir, "farm.txt"), []byte(testMap), 0o644)
+	os.WriteFile(filepath.Join(dir, "broken.txt"), []byte("many ants\n"), 0o644)
+	for name, want := range map[string]int{
+		"farm":    http.StatusOK,
+		"broken":  http.StatusConflict,
+		"missing": http.StatusNotFound,
+	} {
+		resp, err := http.Get(srv.URL + "/maps/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: status %d, want %d", name, resp.StatusCode, want)
+		}
+		if want == http.StatusOK && string(body) != testMap {
+			t.Errorf("%s: body %q", name, body)
+		}
+	}
+	// PUT поверх неразбираемой карты тоже 409
+	if status, _ := putMap(t, srv.URL+"/maps/broken", `{"ops": []}`); status != http.StatusConflict {
+		t.Errorf("PUT broken: status %d", status)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+
+	lib "lem-in/helpers"
+)
+
+// resolveFile — файл из параметра запроса или файл, с которым запущен сервер
+func resolveFile(qFile, defaultFile string) string {
+	useFile := defaultFile
+	if qFile != "" {
+		useFile = qFile
+	}
+	// make absolute path if needed
+	if !filepath.IsAbs(useFile) {
+		useFile = filepath.Join(".", useFile)
+	}
+	return useFile
+}
+
+// buildView — парсит и решает карту, данные для отрисовки без браузера
+func buildView(ctx context.Context, fileName, solverName string, heat bool) (*lib.FarmView, error) {
+	farm, err := parseG(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := solveFarm(ctx, farm, solverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.NewFarmView(farm, plan, heat), nil
+}
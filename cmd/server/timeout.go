@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	lib "lem-in/helpers"
+)
+
+// withSolveTimeout — контекст решения: отменяется вместе с запросом и по таймауту
+func withSolveTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+type timeoutJSON struct {
+	Error      string   `json:"error"`
+	PathsFound int      `json:"pathsFound"`
+	Paths      []string `json:"paths"`
+}
+
+// writeSolveError — 503 с частичной диагностикой при таймауте, тишина при отключении
+// клиента, 400 для ошибок формата карты.
+func writeSolveError(w http.ResponseWriter, r *http.Request, err error) {
+	var se *lib.SolveError
+	switch {
+	case errors.Is(err, context.Canceled) && r.Context().Err() != nil:
+		log.Printf("%s %s: client disconnected, solve cancelled", r.Method, r.URL)
+	case errors.As(err, &se) || errors.Is(err, context.DeadlineExceeded):
+		resp := timeoutJSON{Error: err.Error(), Paths: []string{}}
+		if se != nil {
+			resp.PathsFound = se.Found
+			for _, p := range se.Paths[:min(len(se.Paths), lib.MaxReportedPaths)] {
+				resp.Paths = append(resp.Paths, strings.Join(p, "-"))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(resp)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
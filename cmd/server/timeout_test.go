@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lib "lem-in/helpers"
+)
+
+// gridMap — решётка n×n: путей столько, что dfs не успевает за таймаут
+func gridMap(n int) string {
+	var b strings.Builder
+	b.WriteString("10\n")
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			switch {
+			case x == 0 && y == 0:
+				b.WriteString("##start\n")
+			case x == n-1 && y == n-1:
+				b.WriteString("##end\n")
+			}
+			fmt.Fprintf(&b, "r%d_%d %d %d\n", x, y, x, y)
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if x+1 < n {
+				fmt.Fprintf(&b, "r%d_%d-r%d_%d\n", x, y, x+1, y)
+			}
+			if y+1 < n {
+				fmt.Fprintf(&b, "r%d_%d-r%d_%d\n", x, y, x, y+1)
+			}
+		}
+	}
+	return b.String()
+}
+
+func TestWriteSolveError(t *testing.T) {
+	var paths [][]string
+	for i := 0; i < 30; i++ {
+		paths = append(paths, []string{"s", fmt.Sprint(i), "e"})
+	}
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		found  int
+		paths  int
+	}{
+		{"partial paths", &lib.SolveError{Err: context.DeadlineExceeded, Found: 1000, Paths: paths}, http.StatusServiceUnavailable, 1000, lib.MaxReportedPaths},
+		{"deadline", context.DeadlineExceeded, http.StatusServiceUnavailable, 0, 0},
+		{"bad map", errors.New("invalid number of ants"), http.StatusBadRequest, 0, 0},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		writeSolveError(w, httptest.NewRequest("GET", "/data", nil), tt.err)
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.status)
+			continue
+		}
+		if tt.status != http.StatusServiceUnavailable {
+			continue
+		}
+		var resp timeoutJSON
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if resp.PathsFound != tt.found || len(resp.Paths) != tt.paths || resp.Paths == nil {
+			t.Errorf("%s: %+v", tt.name, resp)
+		}
+		if tt.paths > 0 && resp.Paths[0] != "s-0-e" {
+			t.Errorf("%s: path %q", tt.name, resp.Paths[0])
+		}
+	}
+
+	// клиент отключился — ответ не пишется
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	writeSolveError(w, httptest.NewRequest("GET", "/data", nil).WithContext(ctx), context.Canceled)
+	if w.Body.Len() != 0 || w.Code != http.StatusOK {
+		t.Fatalf("disconnected client got %d %q", w.Code, w.Body)
+	}
+}
+
+func dataServer(t *testing.T, timeout time.Duration) (*httptest.Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "grid.txt"), []byte(gridMap(7)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "small.txt"), []byte(testMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache := newSolutionCache(4, "")
+	srv := httptest.NewServer(handleData(filepath.Join(dir, "small.txt"), cache, newJobQueue(1, 1, cache, timeout)))
+	t.Cleanup(srv.Close)
+	return srv, dir
+}
+
+func TestDataTimeout(t *testing.T) {
+	srv, dir := dataServer(t, 100*time.Millisecond)
+	start := time.Now()
+	resp, err := http.Get(srv.URL + "/data?file=" + filepath.Join(dir, "grid.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("503 after %v", elapsed)
+	}
+	var body timeoutJSON
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, %v", resp.StatusCode, err)
+	}
+	if body.PathsFound <= len(body.Paths) || len(body.Paths) != lib.MaxReportedPaths {
+		t.Fatalf("pathsFound %d, %d paths", body.PathsFound, len(body.Paths))
+	}
+}
+
+func TestDataClientDisconnect(t *testing.T) {
+	srv, dir := dataServer(t, 0)
+
+	// без таймаута решения решётка считалась бы очень долго: его прерывает только уход клиента
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/data?file="+filepath.Join(dir, "grid.txt"), nil)
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+		t.Fatalf("request finished: status %d", resp.StatusCode)
+	}
+
+	// единственный воркер освободился — следующий запрос решается сразу
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(srv.URL + "/data")
+	if err != nil {
+		t.Fatalf("worker still busy: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+}
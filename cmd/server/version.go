@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	lib "lem-in/helpers"
+)
+
+type versionJSON struct {
+	Module    string `json:"module"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// GET /version — информация о сборке (модуль, версия Go, коммит из VCS)
+func handleVersion(w http.ResponseWriter, r *http.Request) {
+	v := versionJSON{Version: "(devel)", GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		v.Module = info.Main.Path
+		if info.Main.Version != "" {
+			v.Version = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				v.Revision = s.Value
+			case "vcs.time":
+				v.Time = s.Value
+			case "vcs.modified":
+				v.Modified = s.Value == "true"
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// GET /solvers — доступные стратегии для ?solver=
+func handleSolvers(w http.ResponseWriter, r *http.Request) {
+	type solverJSON struct {
+		lib.SolverInfo
+		Default bool `json:"default"`
+	}
+	list := []solverJSON{}
+	for _, s := range lib.Solvers() {
+		list = append(list, solverJSON{s, s.Name == lib.DefaultSolver})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(list)
+}
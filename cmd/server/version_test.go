@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"lem-in/web"
+)
+
+func TestVersion(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleVersion(w, httptest.NewRequest("GET", "/version", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	var v versionJSON
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.GoVersion != runtime.Version() || v.Version == "" || v.Module == "" {
+		t.Fatalf("version = %+v", v)
+	}
+}
+
+func TestEmbeddedStatic(t *testing.T) {
+	// из чужого каталога файлов web/ на диске не видно — отдаётся встроенная копия
+	t.Chdir(t.TempDir())
+	static, source := staticFiles("")
+	if source != "embedded assets" {
+		t.Fatalf("source %q", source)
+	}
+	srv := httptest.NewServer(http.FileServer(static))
+	defer srv.Close()
+
+	for _, name := range []string{"visual.html", "script.js", "style.css"} {
+		resp, err := http.Get(srv.URL + "/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		want, err := web.FS.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || string(body) != string(want) {
+			t.Errorf("%s: status %d, %d bytes, want %d", name, resp.StatusCode, len(body), len(want))
+		}
+	}
+	resp, err := http.Get(srv.URL + "/missing.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing file: status %d", resp.StatusCode)
+	}
+}
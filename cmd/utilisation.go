@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	lib "lem-in/helpers"
+)
+
+// runUtilisation — "lem-in utilisation map.txt": загрузка комнат и тоннелей в CSV
+func runUtilisation(args []string) {
+	fs := flag.NewFlagSet("utilisation", flag.ExitOnError)
+	solverName := fs.String("solver", lib.DefaultSolver, "path-finding strategy")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: lem-in utilisation [-solver name] map.txt")
+		os.Exit(1)
+	}
+
+	farm := parseG(fs.Arg(0), lib.ParseOptions{})
+	plan, err := solveFarm(context.Background(), farm, *solverName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	u := lib.ComputeUtilisation(farm, lib.PlanMoves(farm, plan))
+
+	ratio := func(r float64) string { return strconv.FormatFloat(r, 'f', 3, 64) }
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"kind", "name", "uses", "busy_turns", "busy_ratio"})
+	for _, r := range u.Rooms {
+		w.Write([]string{"room", r.Room, strconv.Itoa(r.Visits), strconv.Itoa(r.BusyTurns), ratio(r.BusyRatio)})
+	}
+	for _, l := range u.Links {
+		w.Write([]string{"link", l.From + "-" + l.To, strconv.Itoa(l.Crossings), strconv.Itoa(l.BusyTurns), ratio(l.BusyRatio)})
+	}
+	w.Flush()
+}
@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//--------------------------------------------------------------------------------------|
+
+const DefaultUpstream = "https://programforyou.ru/graph-redactor"
+
+// ErrPatchFailed — ответ содержит SaveFile, но не в том виде, который мы умеем заменять
+// (скорее всего, редактор обновился).
+var ErrPatchFailed = errors.New("graph: SaveFile patch did not apply, upstream editor code has changed")
+
+// saveFileMarker — по нему определяем, что ответ — скрипт редактора, который нужно патчить
+var saveFileMarker = []byte("GraphRedactor.prototype.SaveFile")
+
+//--------------------------------------------------------------------------------------|
+
+// Handler — проксирует запросы на upstream и подменяет SaveFile в скрипте редактора.
+func Handler(upstream *url.URL) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := *upstream
+		u.Path = strings.TrimSuffix(upstream.Path, "/") + r.URL.Path
+		u.RawQuery = r.URL.RawQuery
+
+		req, err := http.NewRequestWithContext(r.Context(), r.Method, u.String(), r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		req.Header = r.Header.Clone()
+		// разжать умеем только gzip
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			http.Error(w, "failed to read response body", http.StatusBadGateway)
+			return
+		}
+
+		isGzip := strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip")
+		if isGzip {
+			body, err = modifyGzipBody(body)
+		} else {
+			body, err = replaceSaveFile(body) // если без сжатия
+		}
+		if errors.Is(err, ErrPatchFailed) {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to modify gzip body: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		for k, v := range resp.Header {
+			if strings.EqualFold(k, "Content-Length") {
+				continue
+			}
+			for _, vv := range v {
+				w.Header().Add(k, vv)
+			}
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+
+		w.WriteHeader(resp.StatusCode)
+		w.Write(body)
+	})
+}
+
+//--------------------------------------------------------------------------------------|
+
+func modifyGzipBody(gzipData []byte) ([]byte, error) {
+	// Разархивируем
+	gzReader, err := gzip.NewReader(bytes.NewReader(gzipData))
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := io.ReadAll(gzReader)
+	gzReader.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Вносим корректировки
+	modified, err := replaceSaveFile(decompressed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Повторно сжимаем
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	if _, err := gzWriter.Write(modified); err != nil {
+		gzWriter.Close()
+		return nil, err
+	}
+	if err := gzWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+//--------------------------------------------------------------------------------------|
+
+var oldSaveFile = []byte(`GraphRedactor.prototype.SaveFile = function() {
+    let graph = {
+        x0: this.x0,
+        y0: this.y0,
+        vertices: this.vertices.ToJSON(),
+        edges: this.edges.map((edge) => edge.ToJSON(this.vertices)),
+        texts: this.texts.map((text) => text.ToJSON())
+    }
+
+    this.SaveObject(new Blob([JSON.stringify(graph)], { type: 'application/octet-stream' }), SAVE_FILE_NAME)
+}`)
+
+var newSaveFile = []byte(`// lignigno
+GraphRedactor.prototype.SaveFile = function() {
+    const vertices = this.vertices.ToJSON();
+    const lines = vertices.map(v => ` + "`${v.name} ${v.x} ${v.y}`" + `);
+
+    const edges = this.edges.map(e => {
+		tmpEdges = e.ToJSON(this.vertices)
+        const from = vertices[tmpEdges.vertex1].name;
+        const to = vertices[tmpEdges.vertex2].name;
+        return ` + "`${from}-${to}`" + `;
+    });
+
+    const content = [...lines, '', ...edges].join('\n');
+    this.SaveObject(new Blob([content], { type: 'text/plain' }), "graph.txt");
+}`)
+
+// replaceSaveFile — тела без SaveFile отдаются как есть; если SaveFile есть,
+// но заменить его не получилось — ErrPatchFailed.
+func replaceSaveFile(body []byte) ([]byte, error) {
+	if !bytes.Contains(body, saveFileMarker) {
+		return body, nil
+	}
+	if !bytes.Contains(body, oldSaveFile) {
+		return nil, ErrPatchFailed
+	}
+	return bytes.Replace(body, oldSaveFile, newSaveFile, 1), nil
+}
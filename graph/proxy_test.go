@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gunzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// newProxy — локальный «редактор» с набором путей и прокси перед ним
+func newProxy(t *testing.T, routes map[string]func(http.ResponseWriter, *http.Request)) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	for p, h := range routes {
+		mux.HandleFunc(p, h)
+	}
+	upstream := httptest.NewServer(mux)
+	t.Cleanup(upstream.Close)
+
+	u, err := url.Parse(upstream.URL + "/graph-redactor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(Handler(u))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+// get — без автоматической распаковки, чтобы видеть ответ как есть
+func get(t *testing.T, rawURL string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func TestHandlerPatchesPlainScript(t *testing.T) {
+	script := append([]byte("let a = 1\n"), oldSaveFile...)
+	proxy := newProxy(t, map[string]func(http.ResponseWriter, *http.Request){
+		"/graph-redactor/js/redactor.js": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RawQuery != "v=2" {
+				t.Errorf("query not forwarded: %q", r.URL.RawQuery)
+			}
+			w.Write(script)
+		},
+	})
+
+	resp, body := get(t, proxy.URL+"/js/redactor.js?v=2")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", resp.StatusCode, body)
+	}
+	if !bytes.Contains(body, newSaveFile) || bytes.Contains(body, oldSaveFile) {
+		t.Fatalf("SaveFile was not replaced:\n%s", body)
+	}
+	if got := resp.Header.Get("Content-Length"); got != "" && got != strconv.Itoa(len(body)) {
+		t.Errorf("Content-Length = %s, body is %d bytes", got, len(body))
+	}
+}
+
+func TestHandlerPatchesGzipScript(t *testing.T) {
+	proxy := newProxy(t, map[string]func(http.ResponseWriter, *http.Request){
+		"/graph-redactor/js/redactor.js": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipBytes(t, oldSaveFile))
+		},
+	})
+
+	resp, body := get(t, proxy.URL+"/js/redactor.js")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q", resp.Header.Get("Content-Encoding"))
+	}
+	if !bytes.Equal(gunzipBytes(t, body), newSaveFile) {
+		t.Fatalf("SaveFile was not replaced in gzip body")
+	}
+}
+
+func TestHandlerPassesThroughOtherContent(t *testing.T) {
+	page := []byte("<html><body>editor</body></html>")
+	proxy := newProxy(t, map[string]func(http.ResponseWriter, *http.Request){
+		"/graph-redactor/": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write(page)
+		},
+	})
+
+	resp, body := get(t, proxy.URL+"/")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if !bytes.Equal(body, page) {
+		t.Fatalf("body changed:\n%s", body)
+	}
+}
+
+func TestHandlerReportsFailedPatch(t *testing.T) {
+	changed := []byte("GraphRedactor.prototype.SaveFile = function() { /* new upstream version */ }")
+	for _, gz := range []bool{false, true} {
+		proxy := newProxy(t, map[string]func(http.ResponseWriter, *http.Request){
+			"/graph-redactor/js/redactor.js": func(w http.ResponseWriter, r *http.Request) {
+				if gz {
+					w.Header().Set("Content-Encoding", "gzip")
+					w.Write(gzipBytes(t, changed))
+					return
+				}
+				w.Write(changed)
+			},
+		})
+
+		resp, body := get(t, proxy.URL+"/js/redactor.js")
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Fatalf("gzip=%v: status = %d, want %d", gz, resp.StatusCode, http.StatusBadGateway)
+		}
+		if !bytes.Contains(body, []byte(ErrPatchFailed.Error())) {
+			t.Fatalf("gzip=%v: body = %q", gz, body)
+		}
+		if bytes.Contains(body, []byte("Unicorn")) {
+			t.Fatalf("gzip=%v: marker appended", gz)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CheckMoves — проверяет ходы по правилам lem-in и возвращает их число:
+// муравей идёт только по тоннелю и не возвращается в start, в комнате (кроме
+// start и end) не больше одного муравья, тоннель за ход используется один раз,
+// все доходят до end.
+func CheckMoves(f *Farm, moves [][]string) (int, error) {
+	pos := map[int]string{} // муравьи, вышедшие из start
+	done := 0
+	for t, turn := range moves {
+		busy := map[string]int{}
+		for ant, room := range pos {
+			if room != f.End {
+				busy[room] = ant
+			}
+		}
+		tunnels := map[string]bool{}
+		moved := map[int]bool{}
+		next := map[int]string{}
+		for _, m := range turn {
+			name, room, ok := strings.Cut(m, "-")
+			ant, err := strconv.Atoi(strings.TrimPrefix(name, "L"))
+			if !ok || err != nil || ant < 1 || ant > f.Ants {
+				return 0, fmt.Errorf("turn %d: bad move %q", t+1, m)
+			}
+			if moved[ant] {
+				return 0, fmt.Errorf("turn %d: ant %d moves twice", t+1, ant)
+			}
+			moved[ant] = true
+			from, ok := pos[ant]
+			if !ok {
+				from = f.Start
+			}
+			if room == f.Start {
+				return 0, fmt.Errorf("turn %d: ant %d moves back to %s", t+1, ant, f.Start)
+			}
+			if from == f.End || !Contains(f.Links[from], room) {
+				return 0, fmt.Errorf("turn %d: no tunnel %s-%s for ant %d", t+1, from, room, ant)
+			}
+			tunnel := min(from, room) + "-" + max(from, room)
+			if tunnels[tunnel] {
+				return 0, fmt.Errorf("turn %d: tunnel %s used twice", t+1, tunnel)
+			}
+			tunnels[tunnel] = true
+			next[ant] = room
+		}
+		for ant := range next {
+			if from, ok := pos[ant]; ok && busy[from] == ant {
+				delete(busy, from)
+			}
+		}
+		for ant, room := range next {
+			if room != f.End {
+				if other, ok := busy[room]; ok {
+					return 0, fmt.Errorf("turn %d: room %s holds ants %d and %d", t+1, room, other, ant)
+				}
+				busy[room] = ant
+			} else {
+				done++
+			}
+			pos[ant] = room
+		}
+	}
+	if done != f.Ants {
+		return 0, fmt.Errorf("%d of %d ants reached %s", done, f.Ants, f.End)
+	}
+	return len(moves), nil
+}
+
+// LowerBound — нижняя оценка числа ходов: d + ⌈n/c⌉ - 1, где d — длина
+// кратчайшего пути, c — наибольшее число путей без общих комнат (минимальный
+// разрез). Каждая комната разреза пропускает одного муравья за ход, и
+// муравей, прошедший её последним, дойдёт до end не раньше этого хода.
+func LowerBound(f *Farm) int {
+	ig := NewIndexedGraph(f)
+	d := ig.shortestPath()
+	if d < 0 {
+		return 0
+	}
+	c := ig.maxDisjointPaths(f.Ants)
+	return d + (f.Ants+c-1)/c - 1
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCheckMoves(t *testing.T) {
+	// smallMap: 3 муравья, a — start, b — end, тоннели a-c, c-b, a-b
+	f, err := ParseFarm(context.Background(), strings.NewReader(smallMap), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if turns, err := CheckMoves(f, [][]string{{"L1-b", "L2-c"}, {"L2-b", "L3-b"}}); err != nil || turns != 2 {
+		t.Fatalf("valid moves: %d turns, %v", turns, err)
+	}
+
+	tests := []struct {
+		name  string
+		moves [][]string
+		want  string
+	}{
+		{"room holds two ants", [][]string{{"L1-c"}, {"L2-c"}}, "room c holds ants 1 and 2"},
+		{"no tunnel", [][]string{{"L1-c"}, {"L1-c"}}, "no tunnel c-c for ant 1"},
+		{"tunnel used twice", [][]string{{"L1-b", "L2-b"}}, "tunnel a-b used twice"},
+		{"ant moves twice", [][]string{{"L1-c", "L1-b"}}, "ant 1 moves twice"},
+		{"move out of end", [][]string{{"L1-b"}, {"L1-c"}}, "no tunnel b-c for ant 1"},
+		{"back to start", [][]string{{"L1-c"}, {"L1-a"}}, "ant 1 moves back to a"},
+		{"ant number too big", [][]string{{"L4-b"}}, `bad move "L4-b"`},
+		{"ant number zero", [][]string{{"L0-b"}}, `bad move "L0-b"`},
+		{"malformed move", [][]string{{"X1b"}}, `bad move "X1b"`},
+		{"not all arrived", [][]string{{"L1-b", "L2-c"}, {"L2-b"}}, "2 of 3 ants reached b"},
+	}
+	for _, tt := range tests {
+		_, err := CheckMoves(f, tt.moves)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AntPalette — те же цвета, что baseColors в web/script.js
+var AntPalette = []string{
+	"#e6194b", "#3cb44b", "#ffe119", "#4363d8", "#f58231",
+	"#911eb4", "#46f0f0", "#f032e6", "#bcf60c", "#fabebe",
+	"#008080", "#e6beff", "#9a6324", "#fffac8", "#800000",
+}
+
+// AntColor — цвет муравья как getColorForAnt во фронтенде:
+// первые муравьи берут цвета из палитры, остальные — оттенок по хешу имени.
+func AntColor(name string) color.RGBA {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, name)
+	if n, err := strconv.Atoi(digits); err == nil && n >= 1 && n <= len(AntPalette) {
+		return ParseHexColor(AntPalette[n-1])
+	}
+	hash := 0
+	for _, c := range name {
+		hash += int(c)
+	}
+	return hslToRGB(float64(hash%360), 0.8, 0.5)
+}
+
+// AntColorHex — то же в виде "#rrggbb"
+func AntColorHex(name string) string {
+	c := AntColor(name)
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func ParseHexColor(s string) color.RGBA {
+	var r, g, b uint8
+	fmt.Sscanf(strings.TrimPrefix(s, "#"), "%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+func hslToRGB(h, s, l float64) color.RGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return color.RGBA{
+		R: uint8(math.Round((r + m) * 255)),
+		G: uint8(math.Round((g + m) * 255)),
+		B: uint8(math.Round((b + m) * 255)),
+		A: 0xff,
+	}
+}
+
+// HeatColor — цвет загрузки от 0 (почти белый) до 1 (тёмно-красный) в виде "#rrggbb"
+func HeatColor(ratio float64) string {
+	t := math.Max(0, math.Min(ratio, 1))
+	c := hslToRGB(60*(1-t), 1, 0.95-0.5*t)
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
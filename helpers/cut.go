@@ -0,0 +1,42 @@
+package helpers
+
+// MinCut — узкое место фермы: минимальный набор комнат, без которых end
+// недостижим из start
+type MinCut struct {
+	Rooms      []string // комнаты разреза, в порядке объявления
+	Direct     bool     // есть тоннель start-end: его комнатами не перекрыть
+	Throughput int      // муравьёв за ход в установившемся режиме: комнаты разреза плюс тоннель start-end
+}
+
+// MinVertexCut — минимальный вершинный разрез между start и end (ближайший к
+// start): максимальный поток в сети с раздвоенными комнатами, тоннели без
+// ограничения, и комнаты, вход которых достижим в остаточной сети, а выход — нет.
+func MinVertexCut(f *Farm) *MinCut {
+	ig := NewIndexedGraph(f)
+	net, s, t := ig.splitNet(len(ig.Names) + 1)
+	cut := &MinCut{Rooms: []string{}}
+	for net.augment(s, t) {
+		cut.Throughput++
+	}
+
+	reached := make([]bool, len(net.adj))
+	reached[s] = true
+	queue := []int{s}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, e := range net.adj[v] {
+			if to := net.edges[e].to; net.edges[e].cap > 0 && !reached[to] {
+				reached[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	for v, name := range ig.Names {
+		if v != ig.Start && v != ig.End && reached[2*v] && !reached[2*v+1] {
+			cut.Rooms = append(cut.Rooms, name)
+		}
+	}
+	cut.Direct = Contains(f.Links[f.Start], f.End)
+	return cut
+}
@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMinVertexCut(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		rooms  []string
+		direct bool
+	}{
+		{"direct tunnel", smallMap, []string{"c"}, true},
+		// два пути через общую комнату m: узкое место — m, а не a или b
+		{"bottleneck", "5\n##start\ns 0 0\na 1 0\nb 1 1\nm 2 0\nx 3 0\ny 3 1\n##end\ne 4 0\n" +
+			"s-a\ns-b\na-m\nb-m\nm-x\nm-y\nx-e\ny-e\n", []string{"m"}, false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFarm(context.Background(), strings.NewReader(tt.text), ParseOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cut := MinVertexCut(f)
+		throughput := len(tt.rooms)
+		if tt.direct {
+			throughput++
+		}
+		if !reflect.DeepEqual(cut.Rooms, tt.rooms) || cut.Direct != tt.direct || cut.Throughput != throughput {
+			t.Errorf("%s: cut = %+v", tt.name, cut)
+		}
+	}
+}
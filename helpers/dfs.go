@@ -0,0 +1,37 @@
+package helpers
+
+import "context"
+
+// dfsSolver — исходная стратегия: все простые пути, жадные группы
+// непересекающихся путей и лучшая группа по высоте
+type dfsSolver struct{}
+
+func init() {
+	RegisterSolver("dfs", "all simple paths by DFS, greedy groups of disjoint paths, best group by height (exponential on dense maps)", dfsSolver{})
+}
+
+func (dfsSolver) Solve(ctx context.Context, f *Farm) (*Plan, error) {
+	ig, groups, err := dfsGroups(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return NewPlan("dfs", ig.PathsNames(BestGroupByHeight(groups, f.Ants)), f.Ants), nil
+}
+
+// dfsGroups — группы-кандидаты стратегии dfs, по одной на каждый путь-затравку
+func dfsGroups(ctx context.Context, f *Farm) (*IndexedGraph, [][][]int, error) {
+	progress := progressOf(ctx)
+	progress.SetStage("searching paths")
+	ig := NewIndexedGraph(f)
+	paths, err := ig.SimplePaths(ctx, progress.AddPath)
+	if err != nil {
+		return nil, nil, ig.newSolveError(err, paths)
+	}
+
+	progress.SetStage("grouping paths")
+	groups, err := ig.DisjointGroups(ctx, paths)
+	if err != nil {
+		return nil, nil, ig.newSolveError(err, paths)
+	}
+	return ig, groups, nil
+}
@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gridMap — решётка n×n, start и end в противоположных углах
+func gridMap(n, ants int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n", ants)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			switch {
+			case x == 0 && y == 0:
+				b.WriteString("##start\n")
+			case x == n-1 && y == n-1:
+				b.WriteString("##end\n")
+			}
+			fmt.Fprintf(&b, "r%d_%d %d %d\n", x, y, x, y)
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if x+1 < n {
+				fmt.Fprintf(&b, "r%d_%d-r%d_%d\n", x, y, x+1, y)
+			}
+			if y+1 < n {
+				fmt.Fprintf(&b, "r%d_%d-r%d_%d\n", x, y, x, y+1)
+			}
+		}
+	}
+	return b.String()
+}
+
+func TestDFSCancelReportsFewPaths(t *testing.T) {
+	f, err := ParseFarm(context.Background(), strings.NewReader(gridMap(7, 10)), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = dfsSolver{}.Solve(ctx, f)
+	elapsed := time.Since(start)
+
+	var se *SolveError
+	if !errors.As(err, &se) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want SolveError with deadline", err)
+	}
+	if len(se.Paths) > MaxReportedPaths || se.Found <= len(se.Paths) {
+		t.Fatalf("found %d, reported %d paths", se.Found, len(se.Paths))
+	}
+	for _, p := range se.Paths {
+		if p[0] != f.Start || p[len(p)-1] != f.End {
+			t.Fatalf("reported path %v", p)
+		}
+	}
+	// отмена не должна стоить сравнимо с самим поиском
+	if elapsed > 2*time.Second {
+		t.Fatalf("cancelled solve returned after %v", elapsed)
+	}
+}
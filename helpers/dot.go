@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RenderDOT — ферма в формате Graphviz (neato -n: координаты карты закреплены).
+// Пути выбранной группы выделены цветом, с Heat — комнаты и связи окрашены по загрузке.
+func RenderDOT(w io.Writer, v *FarmView) error {
+	const scale = 72 // точек на единицу координат
+	onPath := v.PathLinks()
+	linkHeat, roomHeat := v.heatByName()
+
+	var b strings.Builder
+	b.WriteString("graph farm {\n")
+	b.WriteString("  node [shape=circle style=filled fillcolor=\"#ffffff\" fontname=\"sans-serif\"];\n")
+	for _, r := range v.Rooms {
+		fill := "#ffffff"
+		label := r.Name
+		switch u, ok := roomHeat[r.Name]; {
+		case r.Name == v.Start:
+			fill = "#4caf50"
+		case r.Name == v.End:
+			fill = "#f44336"
+		case ok:
+			fill = HeatColor(u.BusyRatio)
+			label = fmt.Sprintf("%s\n%.0f%%", r.Name, 100*u.BusyRatio) // %q даст \n
+		}
+		// y в DOT растёт вверх, в карте — вниз
+		fmt.Fprintf(&b, "  %q [pos=\"%d,%d!\" fillcolor=%q label=%q];\n", r.Name, r.X*scale, -r.Y*scale, fill, label)
+	}
+	for _, l := range v.Links {
+		key := linkKey(l[0], l[1])
+		color, width := "#cccccc", 1.0
+		if u, ok := linkHeat[key]; ok {
+			color, width = HeatColor(u.BusyRatio), 1+4*u.BusyRatio
+		} else if i, ok := onPath[key]; ok {
+			color, width = AntPalette[i%len(AntPalette)], 3
+		}
+		fmt.Fprintf(&b, "  %q -- %q [color=%q penwidth=%.1f];\n", l[0], l[1], color, width)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// EchoMap — печатает исходную карту как есть (\r\n -> \n), построчно, не читая
+// файл целиком; пустая первая строка пропускается, в конце — пустая строка.
+func EchoMap(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for i := 0; ; i++ {
+		line, err := br.ReadString('\n')
+		if strings.HasSuffix(line, "\r\n") {
+			line = line[:len(line)-2] + "\n"
+		}
+		if i == 0 && strings.TrimSpace(line) != "" && !strings.HasSuffix(line, "\n") {
+			line += "\n" // первая строка печатается всегда с переводом строки
+		}
+		if i > 0 || strings.TrimSpace(line) != "" {
+			bw.WriteString(line)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	bw.WriteString("\n\n")
+	return bw.Flush()
+}
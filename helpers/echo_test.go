@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEchoMap(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "3\n#c\na 0 0\n", "3\n#c\na 0 0\n\n\n"},
+		{"crlf", "3\r\n#c\r\na 0 0\r\n", "3\n#c\na 0 0\n\n\n"},
+		{"no trailing newline", "3\n#c\na 0 0", "3\n#c\na 0 0\n\n"},
+		{"single line", "3", "3\n\n\n"},
+		{"blank first line", "\n3\na 0 0\n", "3\na 0 0\n\n\n"},
+		{"blank lines kept", "3\n\na 0 0\n", "3\n\na 0 0\n\n\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := EchoMap(&out, strings.NewReader(tt.in)); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, out.String(), tt.want)
+		}
+	}
+}
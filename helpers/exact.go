@@ -0,0 +1,220 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// exactSolver — оптимальное расписание: максимальный поток в сети,
+// развёрнутой по времени (комната × ход). Горизонт T растёт от длины
+// кратчайшего пути, пока все муравьи не успевают дойти до end; муравьи
+// могут ждать в комнатах. Сеть растёт как rooms×T — только для небольших карт.
+type exactSolver struct{}
+
+func init() {
+	RegisterSolver("exact", "optimal schedule by max-flow on a time-expanded network (room x turn), ants may wait; small maps only", exactSolver{})
+}
+
+// exactMove — ребро перехода между комнатами с хода t-1 на ход t
+type exactMove struct {
+	edge     int
+	from, to int
+}
+
+// timeNet — сеть, развёрнутая по времени: слой t — комнаты после хода t.
+// Узлы 0 и 1 — источник и сток, комната v слоя t: вход 2+2(t·R+v), выход +1.
+type timeNet struct {
+	flowNet
+	g     *IndexedGraph
+	ants  int
+	moves [][]exactMove // moves[t] — переходы на ходу t
+}
+
+const (
+	timeSource = 0
+	timeSink   = 1
+)
+
+func (n *timeNet) in(t, v int) int  { return 2 + 2*(t*len(n.g.Names)+v) }
+func (n *timeNet) out(t, v int) int { return n.in(t, v) + 1 }
+
+// capacity — сколько муравьёв одновременно помещается в комнате
+func (n *timeNet) capacity(v int) int {
+	if v == n.g.Start || v == n.g.End {
+		return n.ants
+	}
+	return 1
+}
+
+// addLayer — добавляет слой t (комнаты после хода t) и переходы из слоя t-1
+func (n *timeNet) addLayer(t int) {
+	n.adj = append(n.adj, make([][]int, 2*len(n.g.Names))...)
+	for v := range n.g.Names {
+		if v == n.g.End {
+			// дошедшие муравьи сразу уходят в сток
+			n.add(n.in(t, v), timeSink, n.ants)
+			continue
+		}
+		n.add(n.in(t, v), n.out(t, v), n.capacity(v))
+	}
+	if t == 0 {
+		n.add(timeSource, n.in(0, n.g.Start), n.ants)
+		n.moves = append(n.moves, nil)
+		return
+	}
+	var moves []exactMove
+	for v, nbs := range n.g.Adj {
+		if v == n.g.End {
+			continue
+		}
+		n.add(n.out(t-1, v), n.in(t, v), n.capacity(v)) // ожидание
+		for _, u := range nbs {
+			if u == n.g.Start {
+				continue // возвращаться в start бессмысленно
+			}
+			moves = append(moves, exactMove{len(n.edges), v, u})
+			n.add(n.out(t-1, v), n.in(t, u), 1) // по тоннелю — один муравей за ход
+		}
+	}
+	n.moves = append(n.moves, moves)
+}
+
+// shortestPath — число ходов по кратчайшему пути start->end; -1, если пути нет
+func (g *IndexedGraph) shortestPath() int {
+	dist := make([]int, len(g.Names))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[g.Start] = 0
+	queue := []int{g.Start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, u := range g.Adj[v] {
+			if dist[u] == -1 {
+				dist[u] = dist[v] + 1
+				queue = append(queue, u)
+			}
+		}
+	}
+	return dist[g.End]
+}
+
+func (exactSolver) Solve(ctx context.Context, f *Farm) (*Plan, error) {
+	progress := progressOf(ctx)
+	progress.SetStage("expanding horizon")
+	ig := NewIndexedGraph(f)
+	horizon := ig.shortestPath()
+	if horizon < 0 {
+		return NewPlan("exact", [][]string{}, f.Ants), nil
+	}
+
+	net := &timeNet{flowNet: flowNet{adj: make([][]int, 2)}, g: ig, ants: f.Ants}
+	for t := 0; t <= horizon; t++ {
+		net.addLayer(t)
+	}
+	for flow := 0; ; {
+		for flow < f.Ants {
+			ok, err := net.augmentCtx(ctx, timeSource, timeSink)
+			if err != nil {
+				return nil, &SolveError{Err: err}
+			}
+			if !ok {
+				break
+			}
+			flow++
+			progress.AddPath()
+		}
+		if flow == f.Ants {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, &SolveError{Err: err}
+		}
+		horizon++
+		net.addLayer(horizon)
+	}
+	return net.plan(f), nil
+}
+
+// plan — расписание из потока: муравьи нумеруются в порядке выхода из start
+func (n *timeNet) plan(f *Farm) *Plan {
+	g := n.g
+	at := make([]int, len(g.Names)) // номер муравья в комнате (0 — пусто)
+	next := 1                       // следующий муравей, выходящий из start
+	pos := make([]int, f.Ants+1)    // комната каждого муравья
+	for i := range pos {
+		pos[i] = g.Start
+	}
+	routes := make([][]string, f.Ants+1)
+	plan := &Plan{Solver: "exact", Moves: [][]string{}}
+
+	for t := 1; t < len(n.moves); t++ {
+		// поток по ребру — остаток обратного ребра
+		used := map[[2]int]bool{}
+		for _, m := range n.moves[t] {
+			if n.edges[m.edge^1].cap > 0 {
+				used[[2]int{m.from, m.to}] = true
+			}
+		}
+		type step struct{ ant, to int }
+		var steps []step
+		for _, m := range n.moves[t] {
+			if !used[[2]int{m.from, m.to}] {
+				continue
+			}
+			if used[[2]int{m.to, m.from}] {
+				continue // встречный обмен равносилен ожиданию обоих
+			}
+			ant := at[m.from]
+			if m.from == g.Start {
+				ant = next
+				next++
+			}
+			steps = append(steps, step{ant, m.to})
+		}
+		sort.Slice(steps, func(i, j int) bool { return steps[i].ant < steps[j].ant })
+
+		turn := make([]string, 0, len(steps))
+		for _, s := range steps {
+			if pos[s.ant] != g.Start {
+				at[pos[s.ant]] = 0
+			}
+		}
+		for _, s := range steps {
+			pos[s.ant] = s.to
+			if s.to != g.End {
+				at[s.to] = s.ant
+			}
+			routes[s.ant] = append(routes[s.ant], g.Names[s.to])
+			turn = append(turn, fmt.Sprintf("L%d-%s", s.ant, g.Names[s.to]))
+		}
+		plan.Moves = append(plan.Moves, turn)
+	}
+	for len(plan.Moves) > 0 && len(plan.Moves[len(plan.Moves)-1]) == 0 {
+		plan.Moves = plan.Moves[:len(plan.Moves)-1]
+	}
+	plan.Turns = len(plan.Moves)
+
+	// маршруты без учёта ожидания; одинаковые объединяются
+	count := map[string]int{}
+	for _, r := range routes[1:] {
+		key := strings.Join(r, ",")
+		if count[key] == 0 {
+			plan.Paths = append(plan.Paths, r)
+		}
+		count[key]++
+	}
+	sort.Slice(plan.Paths, func(i, j int) bool {
+		if len(plan.Paths[i]) != len(plan.Paths[j]) {
+			return len(plan.Paths[i]) < len(plan.Paths[j])
+		}
+		return strings.Join(plan.Paths[i], ",") < strings.Join(plan.Paths[j], ",")
+	})
+	for _, p := range plan.Paths {
+		plan.Ants = append(plan.Ants, count[strings.Join(p, ",")])
+	}
+	return plan
+}
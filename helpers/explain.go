@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+)
+
+// GroupCandidate — группа-кандидат стратегии dfs и как по ней распределятся муравьи
+type GroupCandidate struct {
+	Paths     [][]string // без start, с end
+	Combined  []int      // CombinedHeights: длина пути плюс его муравьи
+	Ants      []int      // муравьёв на каждом пути
+	Height    int        // Height: combined первого (кратчайшего) пути
+	Turns     int
+	Duplicate int // индекс такой же группы выше по списку, -1 — нет
+}
+
+// Explanation — все кандидаты в порядке построения и выбранный из них
+type Explanation struct {
+	Candidates []GroupCandidate
+	Winner     int   // -1, если путей нет
+	Tied       []int // другие кандидаты с той же высотой (и другим набором путей)
+}
+
+// ExplainGroups — то же решение, что у стратегии dfs, но со всеми кандидатами:
+// группа строится от каждого пути-затравки (пути по длине, затем по именам
+// комнат), BestGroupByHeight берёт первую с наименьшей высотой.
+func ExplainGroups(ctx context.Context, f *Farm) (*Explanation, error) {
+	ig, groups, err := dfsGroups(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	ex := &Explanation{Winner: -1}
+	seen := map[string]int{}
+	for i, group := range groups {
+		paths := ig.PathsNames(group)
+		c := GroupCandidate{Paths: paths, Duplicate: -1}
+		c.Height, c.Combined = Height(paths, f.Ants)
+		c.Ants = AntHeights(append([]int{}, c.Combined...), PathHeights(paths))
+		c.Turns = PlanTurns(paths, c.Ants)
+
+		key := groupKey(paths)
+		if j, ok := seen[key]; ok {
+			c.Duplicate = j
+		} else {
+			seen[key] = i
+		}
+		if ex.Winner == -1 || c.Height < ex.Candidates[ex.Winner].Height {
+			ex.Winner = i
+		}
+		ex.Candidates = append(ex.Candidates, c)
+	}
+	if ex.Winner >= 0 {
+		for i, c := range ex.Candidates {
+			if i != ex.Winner && c.Duplicate == -1 && c.Height == ex.Candidates[ex.Winner].Height {
+				ex.Tied = append(ex.Tied, i)
+			}
+		}
+	}
+	return ex, nil
+}
+
+func groupKey(paths [][]string) string {
+	keys := make([]string, len(paths))
+	for i, p := range paths {
+		keys[i] = strings.Join(p, ",")
+	}
+	return strings.Join(keys, ";")
+}
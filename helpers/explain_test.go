@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExplainGroupsMatchesDFS(t *testing.T) {
+	for name, f := range exampleFarms(t) {
+		ex, err := ExplainGroups(context.Background(), f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan, err := dfsSolver{}.Solve(context.Background(), f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		best := ex.Candidates[ex.Winner]
+		if !reflect.DeepEqual(best.Paths, plan.Paths) || !reflect.DeepEqual(best.Ants, plan.Ants) {
+			t.Errorf("%s: winner %v %v, dfs %v %v", name, best.Paths, best.Ants, plan.Paths, plan.Ants)
+		}
+		for _, i := range ex.Tied {
+			if i < ex.Winner || ex.Candidates[i].Height != best.Height {
+				t.Errorf("%s: bad tie #%d", name, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package helpers
+
+// Room — комната и её координаты
+type Room struct {
+	Name string
+	X    int
+	Y    int
+}
+
+// Farm — разобранная карта. Каждый разбор создаёт новое значение без общего
+// состояния, поэтому фермы можно разбирать и решать одновременно.
+type Farm struct {
+	Ants  int
+	Start string
+	End   string
+	Rooms []Room              // в порядке объявления в файле
+	Links map[string][]string // соседи каждой комнаты
+	Group [][]string          // выбранная группа путей (без start, с end); nil до решения
+}
+
+// Coords — координаты комнат по имени
+func (f *Farm) Coords() map[string][2]int {
+	coords := make(map[string][2]int, len(f.Rooms))
+	for _, r := range f.Rooms {
+		coords[r.Name] = [2]int{r.X, r.Y}
+	}
+	return coords
+}
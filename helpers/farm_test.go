@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestParallelSolve — разбор и решение разных карт одновременно дают то же,
+// что и последовательно; имеет смысл под go test -race
+func TestParallelSolve(t *testing.T) {
+	files, err := filepath.Glob("../examples/*.txt")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no examples: %v", err)
+	}
+	solve := func(text []byte, name string) (*Plan, error) {
+		f, err := ParseFarm(context.Background(), bytes.NewReader(text), ParseOptions{})
+		if err != nil {
+			return nil, err
+		}
+		s, err := LookupSolver(name)
+		if err != nil {
+			return nil, err
+		}
+		return s.Solve(context.Background(), f)
+	}
+
+	type run struct {
+		file, solver string
+		text         []byte
+		want         *Plan
+	}
+	var runs []run
+	for _, file := range files {
+		text, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range []string{"dfs", "flow", "exact"} {
+			want, err := solve(text, s)
+			if err != nil {
+				continue // некорректные карты из examples/
+			}
+			runs = append(runs, run{filepath.Base(file), s, text, want})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for round := 0; round < 4; round++ {
+		for _, r := range runs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, err := solve(r.text, r.solver)
+				if err != nil {
+					t.Errorf("%s/%s: %v", r.file, r.solver, err)
+					return
+				}
+				if !reflect.DeepEqual(got, r.want) {
+					t.Errorf("%s/%s: parallel plan %+v, sequential %+v", r.file, r.solver, got, r.want)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,184 @@
+package helpers
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// flowSolver — непересекающиеся по комнатам пути через максимальный поток
+// (Эдмондс–Карп, каждая комната раздвоена на вход и выход с пропускной
+// способностью 1). После каждого увеличивающего пути набор путей
+// перестраивается, и остаётся тот, что даёт меньше всего ходов.
+type flowSolver struct{}
+
+func init() {
+	RegisterSolver("flow", "vertex-disjoint paths by max-flow (Edmonds-Karp), best path count for the ants; polynomial", flowSolver{})
+}
+
+type flowEdge struct {
+	to  int
+	cap int
+}
+
+// flowNet — остаточная сеть: рёбра парами (прямое чётное, обратное — e^1)
+type flowNet struct {
+	edges []flowEdge
+	adj   [][]int
+	steps int // вершин, снятых с очереди во всех поисках (для проверки ctx)
+}
+
+func (n *flowNet) add(from, to, cap int) {
+	n.adj[from] = append(n.adj[from], len(n.edges))
+	n.edges = append(n.edges, flowEdge{to, cap})
+	n.adj[to] = append(n.adj[to], len(n.edges))
+	n.edges = append(n.edges, flowEdge{from, 0})
+}
+
+// augment — кратчайший увеличивающий путь s->t в остаточной сети (BFS);
+// false, если пути нет
+func (n *flowNet) augment(s, t int) bool {
+	ok, _ := n.augmentCtx(context.Background(), s, t)
+	return ok
+}
+
+// augmentCtx — augment, который раз в 1024 шага поиска проверяет ctx и при
+// отмене возвращает ctx.Err(), не меняя сеть
+func (n *flowNet) augmentCtx(ctx context.Context, s, t int) (bool, error) {
+	prev := make([]int, len(n.adj))
+	for i := range prev {
+		prev[i] = -1
+	}
+	prev[s] = -2
+	queue := []int{s}
+	for len(queue) > 0 && prev[t] == -1 {
+		v := queue[0]
+		queue = queue[1:]
+		if n.steps++; n.steps%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+		}
+		for _, e := range n.adj[v] {
+			if to := n.edges[e].to; n.edges[e].cap > 0 && prev[to] == -1 {
+				prev[to] = e
+				queue = append(queue, to)
+			}
+		}
+	}
+	if prev[t] == -1 {
+		return false, nil
+	}
+	for v := t; v != s; v = n.edges[prev[v]^1].to {
+		n.edges[prev[v]].cap--
+		n.edges[prev[v]^1].cap++
+	}
+	return true, nil
+}
+
+// splitNet — сеть с раздвоенными комнатами (вход 2v, выход 2v+1, пропускная
+// способность 1) и её исток и сток. linkCap — пропускная способность тоннелей;
+// тоннель start-end всегда пропускает одного муравья за ход.
+func (g *IndexedGraph) splitNet(linkCap int) (net *flowNet, s, t int) {
+	net = &flowNet{adj: make([][]int, 2*len(g.Names))}
+	for v := range g.Names {
+		net.add(2*v, 2*v+1, 1)
+	}
+	for v, nbs := range g.Adj {
+		for _, u := range nbs {
+			if v == g.Start && u == g.End {
+				net.add(2*v+1, 2*u, 1)
+			} else {
+				net.add(2*v+1, 2*u, linkCap)
+			}
+		}
+	}
+	return net, 2*g.Start + 1, 2 * g.End
+}
+
+// maxDisjointPaths — сколько путей start->end без общих комнат (не больше limit)
+func (g *IndexedGraph) maxDisjointPaths(limit int) int {
+	net, s, t := g.splitNet(1)
+	n := 0
+	for n < limit && net.augment(s, t) {
+		n++
+	}
+	return n
+}
+
+func (flowSolver) Solve(ctx context.Context, f *Farm) (*Plan, error) {
+	progress := progressOf(ctx)
+	progress.SetStage("augmenting paths")
+	ig := NewIndexedGraph(f)
+	net, s, t := ig.splitNet(1)
+
+	var best *Plan
+	for best == nil || len(best.Paths) < f.Ants {
+		if err := ctx.Err(); err != nil {
+			se := &SolveError{Err: err}
+			if best != nil {
+				se.Found = len(best.Paths)
+				for _, p := range best.Paths[:min(len(best.Paths), MaxReportedPaths)] {
+					se.Paths = append(se.Paths, append([]string{f.Start}, p...))
+				}
+			}
+			return nil, se
+		}
+		if !net.augment(s, t) {
+			break
+		}
+		progress.AddPath()
+		plan := NewPlan("flow", flowPaths(net, ig, s, t), f.Ants)
+		if best == nil || plan.Turns < best.Turns {
+			best = plan
+		}
+	}
+	if best == nil {
+		return NewPlan("flow", [][]string{}, f.Ants), nil
+	}
+	return best, nil
+}
+
+// flowPaths — разложение текущего потока на пути (без start, с end),
+// по длине, при равной — по именам комнат
+func flowPaths(net *flowNet, ig *IndexedGraph, s, t int) [][]string {
+	used := make([]bool, len(net.edges))
+	var paths [][]string
+	for _, first := range net.adj[s] {
+		if first%2 != 0 || net.edges[first].cap != 0 {
+			continue // не прямое ребро или по нему нет потока
+		}
+		used[first] = true
+		path := []string{}
+		for v := net.edges[first].to; ; {
+			path = append(path, ig.Names[v/2])
+			if v == t {
+				break
+			}
+			// вход комнаты -> её выход -> следующая комната с потоком
+			next := -1
+			for _, e := range net.adj[v+1] {
+				if e%2 == 0 && net.edges[e].cap == 0 && !used[e] && net.edges[e].to != v {
+					next = e
+					break
+				}
+			}
+			if next == -1 {
+				path = nil
+				break
+			}
+			used[next] = true
+			v = net.edges[next].to
+		}
+		if path != nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return strings.Join(paths[i], ",") < strings.Join(paths[j], ",")
+	})
+	return paths
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// checkFlowPlan — ходы плана flow проходят CheckMoves за plan.Turns ходов
+func checkFlowPlan(t *testing.T, name string, f *Farm) *Plan {
+	t.Helper()
+	plan, err := flowSolver{}.Solve(context.Background(), f)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	turns, err := CheckMoves(f, SimulateMoves(f, plan.Paths))
+	if err != nil {
+		t.Fatalf("%s: invalid moves: %v", name, err)
+	}
+	if turns != plan.Turns {
+		t.Errorf("%s: moves take %d turns, plan says %d", name, turns, plan.Turns)
+	}
+	return plan
+}
+
+func TestFlowSolverOnExamples(t *testing.T) {
+	farms := exampleFarms(t)
+	names := make([]string, 0, len(farms))
+	for name := range farms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	dfs, err := LookupSolver("dfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		f := farms[name]
+		flow := checkFlowPlan(t, name, f)
+		heuristic, err := dfs.Solve(context.Background(), f)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if flow.Turns > heuristic.Turns {
+			t.Errorf("%s: flow %d turns, dfs %d", name, flow.Turns, heuristic.Turns)
+		}
+	}
+}
+
+func TestFlowSolverDirectTunnel(t *testing.T) {
+	// тоннель start-end пропускает одного муравья за ход, обход через a — второго
+	text := "5\n##start\ns 0 0\na 1 1\n##end\ne 2 0\ns-e\ns-a\na-e\n"
+	f, err := ParseFarm(context.Background(), strings.NewReader(text), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan := checkFlowPlan(t, "direct", f); plan.Turns != 3 || len(plan.Paths) != 2 {
+		t.Fatalf("plan = %+v, want 3 turns on 2 paths", plan)
+	}
+}
@@ -0,0 +1,57 @@
+package helpers
+
+import "sort"
+
+// FormatMap — приводит карту к каноническому виду: число муравьёв первой строкой,
+// ##start/##end прямо перед своими комнатами, комнаты в исходном порядке,
+// связи без дублей, отсортированы и ориентированы по порядку объявления комнат,
+// комментарии остаются перед той же строкой, переводы строк — только "\n".
+func FormatMap(text string) (string, error) {
+	m, err := ParseMapFile(text)
+	if err != nil {
+		return "", err
+	}
+	m.normalizeLinks()
+	return m.String(), nil
+}
+
+func (m *MapFile) normalizeLinks() {
+	order := make(map[string]int, len(m.Rooms))
+	for i, r := range m.Rooms {
+		order[r.Name] = i
+	}
+	less := func(a, b string) bool {
+		ia, okA := order[a]
+		ib, okB := order[b]
+		if okA && okB {
+			return ia < ib
+		}
+		if okA != okB {
+			return okA
+		}
+		return a < b
+	}
+
+	links := make([]MapLink, 0, len(m.Links))
+	seen := make(map[[2]string]int, len(m.Links))
+	for _, l := range m.Links {
+		if less(l.To, l.From) {
+			l.From, l.To = l.To, l.From
+		}
+		key := [2]string{l.From, l.To}
+		if i, ok := seen[key]; ok {
+			links[i].Comments = append(links[i].Comments, l.Comments...)
+			continue
+		}
+		seen[key] = len(links)
+		links = append(links, l)
+	}
+
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].From != links[j].From {
+			return less(links[i].From, links[j].From)
+		}
+		return less(links[i].To, links[j].To)
+	})
+	m.Links = links
+}
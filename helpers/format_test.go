@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatMap(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			"directives and comments",
+			"2\r\n# farm\r\n\r\n##end\r\ne 2 0\r\n#mid\r\nm 1 0\r\n##start\r\n  s 0 0  \r\nm-s\r\n#tail\r\n",
+			"2\n# farm\n##end\ne 2 0\n#mid\nm 1 0\n##start\ns 0 0\nm-s\n#tail\n",
+		},
+		{
+			// комментарий между директивой и комнатой остаётся перед той же комнатой
+			"comment after directive",
+			"1\n##start\n# note\ns 0 0\n##end\ne 1 0\ns-e\n",
+			"1\n# note\n##start\ns 0 0\n##end\ne 1 0\ns-e\n",
+		},
+		{
+			// комментарии перед числом муравьёв остаются перед ним
+			"leading comments",
+			"# generated\n#by hand\n3\n##start\ns 0 0\n##end\ne 1 0\ns-e\n",
+			"# generated\n#by hand\n3\n##start\ns 0 0\n##end\ne 1 0\ns-e\n",
+		},
+		{
+			// ориентация — по порядку объявления комнат, а не по алфавиту
+			"orientation",
+			"1\n##start\nz 0 0\n##end\na 1 0\nm 2 0\nm-a\na-z\nm-z\n",
+			"1\n##start\nz 0 0\n##end\na 1 0\nm 2 0\nz-a\nz-m\na-m\n",
+		},
+		{
+			// дубликат a-b/b-a схлопывается, его комментарий переходит к оставшейся связи
+			"duplicates",
+			"1\n##start\ns 0 0\n##end\ne 2 0\nm 1 0\nm-e\ne-s\n# again\ne-m\ns-m\n",
+			"1\n##start\ns 0 0\n##end\ne 2 0\nm 1 0\ns-e\ns-m\n# again\ne-m\n",
+		},
+		{
+			"undeclared room",
+			"1\n##start\ns 0 0\n##end\ne 1 0\nz-s\ns-e\n",
+			"1\n##start\ns 0 0\n##end\ne 1 0\ns-e\ns-z\n",
+		},
+	}
+	for _, tt := range tests {
+		got, err := FormatMap(tt.text)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMapIdempotent(t *testing.T) {
+	files, err := filepath.Glob("../examples/*.txt")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no examples: %v", err)
+	}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		once, err := FormatMap(string(raw))
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		twice, err := FormatMap(once)
+		if err != nil {
+			t.Errorf("%s: formatted map does not parse: %v", file, err)
+			continue
+		}
+		if twice != once {
+			t.Errorf("%s: fmt(fmt(x)) != fmt(x)", file)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package helpers
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+)
+
+var (
+	colWhite = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colBlack = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	colLink  = color.RGBA{0xcc, 0xcc, 0xcc, 0xff}
+	colStart = ParseHexColor("#4caf50")
+	colEnd   = ParseHexColor("#f44336")
+)
+
+// RenderGIF — анимация всего прогона: кадр на каждый ход и inBetween промежуточных
+// кадров между ходами (муравьи плавно движутся по связям). delay — сотые доли секунды на ход.
+func RenderGIF(w io.Writer, v *FarmView, inBetween, delay int) error {
+	const roomR, antR = 12, 7
+	pos, width, height := v.Layout(600, 40)
+	bounds := image.Rect(0, 0, int(width), int(height)+30)
+
+	// фон со связями и комнатами один на все кадры
+	bg := image.NewRGBA(bounds)
+	draw.Draw(bg, bounds, image.NewUniform(colWhite), image.Point{}, draw.Src)
+	onPath := v.PathLinks()
+	for _, l := range v.Links {
+		if _, ok := onPath[linkKey(l[0], l[1])]; !ok {
+			drawThickLine(bg, pos[l[0]], pos[l[1]], 1, colLink)
+		}
+	}
+	for _, l := range v.Links {
+		if i, ok := onPath[linkKey(l[0], l[1])]; ok {
+			drawThickLine(bg, pos[l[0]], pos[l[1]], 2, blend(ParseHexColor(AntPalette[i%len(AntPalette)]), colWhite, 0.4))
+		}
+	}
+	for _, r := range v.Rooms {
+		fill := colWhite
+		if r.Name == v.Start {
+			fill = colStart
+		} else if r.Name == v.End {
+			fill = colEnd
+		}
+		p := pos[r.Name]
+		fillCircle(bg, p[0], p[1], roomR, colBlack)
+		fillCircle(bg, p[0], p[1], roomR-2, fill)
+	}
+
+	pal := gifPalette(v)
+	frameDelay := max(1, delay/(inBetween+1))
+	anim := &gif.GIF{}
+	addFrame := func(img *image.RGBA, d int) {
+		pm := image.NewPaletted(bounds, pal)
+		draw.Draw(pm, bounds, img, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, pm)
+		anim.Delay = append(anim.Delay, d)
+	}
+
+	for t := 0; t <= len(v.Turns); t++ {
+		ants := AntsAt(v.Turns, v.Start, t)
+		steps := []float64{1}
+		if t > 0 {
+			steps = steps[:0]
+			for f := 1; f <= inBetween+1; f++ {
+				steps = append(steps, float64(f)/float64(inBetween+1))
+			}
+		}
+		for _, progress := range steps {
+			img := image.NewRGBA(bounds)
+			copy(img.Pix, bg.Pix)
+
+			count := map[string]int{v.Start: v.Ants - len(ants)}
+			for _, a := range ants {
+				arrived := a.Room == v.End && (progress == 1 || a.Prev == v.End)
+				if arrived {
+					count[v.End]++
+					continue
+				}
+				from, to := pos[a.Prev], pos[a.Room]
+				x := from[0] + (to[0]-from[0])*progress
+				y := from[1] + (to[1]-from[1])*progress
+				fillCircle(img, x, y, antR, AntColor(a.Name))
+			}
+			for _, name := range []string{v.Start, v.End} {
+				p := pos[name]
+				drawText(img, int(p[0])+roomR+3, int(p[1])-roomR-12, fmt.Sprint(count[name]), 2, colBlack)
+			}
+			drawText(img, 10, bounds.Dy()-22, fmt.Sprintf("Turn %d/%d", t, len(v.Turns)), 3, colBlack)
+
+			d := frameDelay
+			if t == len(v.Turns) && progress == 1 {
+				d = delay * 3 // задержка на последнем кадре
+			}
+			addFrame(img, d)
+		}
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// gifPalette — точные цвета фермы и муравьёв, если помещаются в 256, иначе Plan9
+func gifPalette(v *FarmView) color.Palette {
+	pal := color.Palette{colWhite, colBlack, colLink, colStart, colEnd}
+	seen := map[color.Color]bool{}
+	for _, c := range pal {
+		seen[c] = true
+	}
+	addColor := func(c color.RGBA) {
+		if !seen[c] {
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+	for i := range v.Paths {
+		addColor(blend(ParseHexColor(AntPalette[i%len(AntPalette)]), colWhite, 0.4))
+	}
+	for i := 1; i <= v.Ants; i++ {
+		addColor(AntColor(fmt.Sprintf("L%d", i)))
+		if len(pal) > 256 {
+			return palette.Plan9
+		}
+	}
+	return pal
+}
+
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 { return uint8(math.Round(float64(x)*(1-t) + float64(y)*t)) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
+
+func fillCircle(img *image.RGBA, cx, cy, r float64, c color.RGBA) {
+	for y := int(cy - r); y <= int(cy+r); y++ {
+		for x := int(cx - r); x <= int(cx+r); x++ {
+			dx, dy := float64(x)-cx, float64(y)-cy
+			if dx*dx+dy*dy <= r*r {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func drawThickLine(img *image.RGBA, a, b [2]float64, half int, c color.RGBA) {
+	steps := int(math.Max(math.Abs(b[0]-a[0]), math.Abs(b[1]-a[1]))) + 1
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(a[0] + (b[0]-a[0])*t))
+		y := int(math.Round(a[1] + (b[1]-a[1])*t))
+		for dy := -half; dy <= half; dy++ {
+			for dx := -half; dx <= half; dx++ {
+				img.SetRGBA(x+dx, y+dy, c)
+			}
+		}
+	}
+}
+
+// glyphs — растровый шрифт 3×5 для счётчика ходов (только нужные символы)
+var glyphs = map[rune][5]string{
+	'0': {"111", "101", "101", "101", "111"},
+	'1': {"010", "110", "010", "010", "111"},
+	'2': {"111", "001", "111", "100", "111"},
+	'3': {"111", "001", "111", "001", "111"},
+	'4': {"101", "101", "111", "001", "001"},
+	'5': {"111", "100", "111", "001", "111"},
+	'6': {"111", "100", "111", "101", "111"},
+	'7': {"111", "001", "001", "001", "001"},
+	'8': {"111", "101", "111", "101", "111"},
+	'9': {"111", "101", "111", "001", "111"},
+	'/': {"001", "001", "010", "100", "100"},
+	'T': {"111", "010", "010", "010", "010"},
+	'u': {"000", "101", "101", "101", "111"},
+	'r': {"000", "111", "100", "100", "100"},
+	'n': {"000", "110", "101", "101", "101"},
+}
+
+func drawText(img *image.RGBA, x, y int, text string, scale int, c color.RGBA) {
+	for _, ch := range text {
+		g, ok := glyphs[ch]
+		if ok {
+			for row, bits := range g {
+				for col, bit := range bits {
+					if bit != '1' {
+						continue
+					}
+					for dy := 0; dy < scale; dy++ {
+						for dx := 0; dx < scale; dx++ {
+							img.SetRGBA(x+col*scale+dx, y+row*scale+dy, c)
+						}
+					}
+				}
+			}
+		}
+		x += 4 * scale
+	}
+}
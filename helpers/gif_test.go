@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/gif"
+	"reflect"
+	"testing"
+)
+
+func TestRenderGIF(t *testing.T) {
+	v := svgView(t) // 2 муравья, 3 хода
+	for _, frames := range []int{0, 3} {
+		var buf bytes.Buffer
+		if err := RenderGIF(&buf, v, frames, 40); err != nil {
+			t.Fatal(err)
+		}
+		anim, err := gif.DecodeAll(&buf)
+		if err != nil {
+			t.Fatalf("frames=%d: %v", frames, err)
+		}
+		// начальный кадр, затем frames промежуточных и итоговый на каждый ход
+		want := len(v.Turns)*(frames+1) + 1
+		if len(anim.Image) != want || len(anim.Delay) != want {
+			t.Fatalf("frames=%d: %d images, %d delays, want %d", frames, len(anim.Image), len(anim.Delay), want)
+		}
+		step := max(1, 40/(frames+1))
+		for i, d := range anim.Delay[:want-1] {
+			if d != step {
+				t.Errorf("frames=%d: delay[%d] = %d, want %d", frames, i, d, step)
+			}
+		}
+		if last := anim.Delay[want-1]; last != 120 {
+			t.Errorf("frames=%d: last delay %d, want 120", frames, last)
+		}
+
+		// палитра фермы дополняется энкодером до степени двойки
+		pal := gifPalette(v)
+		for i, img := range anim.Image {
+			got := img.Palette
+			if len(got) != 8 || !reflect.DeepEqual(rgba(got[:len(pal)]), rgba(pal)) {
+				t.Fatalf("frames=%d: frame %d palette %v, want %v", frames, i, got, pal)
+			}
+		}
+	}
+}
+
+func TestGIFPaletteManyAnts(t *testing.T) {
+	// цвета муравьёв повторяются, так что и тысяча муравьёв укладывается в 256 точных цветов
+	v := &FarmView{Ants: 1000, Paths: [][]string{{"e"}}}
+	pal := gifPalette(v)
+	if len(pal) > 256 {
+		t.Fatalf("%d colours", len(pal))
+	}
+	for i := 1; i <= v.Ants; i++ {
+		c := AntColor(fmt.Sprintf("L%d", i))
+		if pal.Convert(c) != c {
+			t.Fatalf("L%d colour %v is approximated", i, c)
+		}
+	}
+}
+
+// rgba — цвета палитры в сравнимом виде (декодер возвращает color.RGBA)
+func rgba(p color.Palette) []color.RGBA {
+	ans := make([]color.RGBA, len(p))
+	for i, c := range p {
+		ans[i] = color.RGBAModel.Convert(c).(color.RGBA)
+	}
+	return ans
+}
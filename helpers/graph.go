@@ -0,0 +1,152 @@
+package helpers
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// IndexedGraph — ферма с комнатами-индексами для решателя: соседи хранятся
+// срезами, имена нужны только на входе и выходе.
+type IndexedGraph struct {
+	Names []string       // id -> имя, в порядке объявления комнат
+	IDs   map[string]int // имя -> id
+	Adj   [][]int        // соседи по id, в порядке объявления связей
+	Start int
+	End   int
+}
+
+// NewIndexedGraph — интернирует комнаты и связи фермы
+func NewIndexedGraph(f *Farm) *IndexedGraph {
+	g := &IndexedGraph{
+		Names: make([]string, len(f.Rooms)),
+		IDs:   make(map[string]int, len(f.Rooms)),
+		Adj:   make([][]int, len(f.Rooms)),
+	}
+	for i, r := range f.Rooms {
+		g.Names[i] = r.Name
+		g.IDs[r.Name] = i
+	}
+	for i, name := range g.Names {
+		links := f.Links[name]
+		g.Adj[i] = make([]int, 0, len(links))
+		for _, nb := range links {
+			if id, ok := g.IDs[nb]; ok {
+				g.Adj[i] = append(g.Adj[i], id)
+			}
+		}
+	}
+	g.Start = g.IDs[f.Start]
+	g.End = g.IDs[f.End]
+	return g
+}
+
+// PathNames — путь из id в имена комнат
+func (g *IndexedGraph) PathNames(path []int) []string {
+	names := make([]string, len(path))
+	for i, id := range path {
+		names[i] = g.Names[id]
+	}
+	return names
+}
+
+// PathsNames — PathNames для списка путей
+func (g *IndexedGraph) PathsNames(paths [][]int) [][]string {
+	out := make([][]string, len(paths))
+	for i, p := range paths {
+		out[i] = g.PathNames(p)
+	}
+	return out
+}
+
+// SimplePaths — все простые пути start->end (вместе с start и end) в порядке
+// обхода в глубину. found, если задан, вызывается на каждый найденный путь.
+// При отмене ctx возвращает уже найденные пути и ctx.Err().
+func (g *IndexedGraph) SimplePaths(ctx context.Context, found func()) ([][]int, error) {
+	ans := [][]int{}
+	path := []int{g.Start}
+	visited := make([]bool, len(g.Names))
+	visited[g.Start] = true
+	steps, stopped := 0, false
+
+	var dfs func(int)
+	dfs = func(cur int) {
+		steps++
+		if stopped || (steps%1024 == 0 && ctx.Err() != nil) {
+			stopped = true
+			return
+		}
+		if cur == g.End {
+			ans = append(ans, append([]int{}, path...))
+			if found != nil {
+				found()
+			}
+			return
+		}
+		for _, nb := range g.Adj[cur] {
+			if !visited[nb] {
+				visited[nb] = true
+				path = append(path, nb)
+				dfs(nb)
+				path = path[:len(path)-1]
+				visited[nb] = false
+			}
+		}
+	}
+	dfs(g.Start)
+	return ans, ctx.Err()
+}
+
+// DisjointGroups — жадные группы путей без общих комнат. Пути сортируются по
+// длине (при равной — по именам комнат); i-я группа начинается с i-го пути и
+// добирает все следующие, не пересекающиеся с уже взятыми. В группах пути
+// без start, но с end.
+func (g *IndexedGraph) DisjointGroups(ctx context.Context, paths [][]int) ([][][]int, error) {
+	keys := make([]string, len(paths))
+	order := make([]int, len(paths))
+	for i, p := range paths {
+		keys[i] = strings.Join(g.PathNames(p), ",")
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if len(paths[a]) != len(paths[b]) {
+			return len(paths[a]) < len(paths[b])
+		}
+		return keys[a] < keys[b]
+	})
+	sorted := make([][]int, len(paths))
+	for i, idx := range order {
+		sorted[i] = paths[idx][1:]
+	}
+
+	// mark[id] == i+1 — комната занята в i-й группе; без очистки между группами
+	mark := make([]int, len(g.Names))
+	groups := make([][][]int, 0, len(sorted))
+	for i := range sorted {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		stamp := i + 1
+		group := [][]int{}
+		for _, p := range sorted[i:] {
+			inner := p[:len(p)-1]
+			conflict := false
+			for _, id := range inner {
+				if mark[id] == stamp {
+					conflict = true
+					break
+				}
+			}
+			if conflict {
+				continue
+			}
+			for _, id := range inner {
+				mark[id] = stamp
+			}
+			group = append(group, p)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
@@ -0,0 +1,171 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// genFarm — связная ферма: кольцо из rooms комнат плюс случайные связи до links
+func genFarm(rooms, links int, seed int64) *Farm {
+	rnd := rand.New(rand.NewSource(seed))
+	f := &Farm{Ants: 100, Links: make(map[string][]string, rooms)}
+	for i := 0; i < rooms; i++ {
+		name := fmt.Sprintf("r%d", i)
+		f.Rooms = append(f.Rooms, Room{Name: name, X: rnd.Intn(1000), Y: rnd.Intn(1000)})
+	}
+	f.Start, f.End = f.Rooms[0].Name, f.Rooms[rooms/2].Name
+
+	seen := map[[2]int]bool{}
+	link := func(a, b int) {
+		if a == b || seen[[2]int{a, b}] || seen[[2]int{b, a}] {
+			return
+		}
+		seen[[2]int{a, b}] = true
+		na, nb := f.Rooms[a].Name, f.Rooms[b].Name
+		f.Links[na] = append(f.Links[na], nb)
+		f.Links[nb] = append(f.Links[nb], na)
+	}
+	for i := 0; i < rooms; i++ {
+		link(i, (i+1)%rooms)
+	}
+	for len(seen) < links {
+		link(rnd.Intn(rooms), rnd.Intn(rooms))
+	}
+	return f
+}
+
+// mapSimplePaths — прежний обход по map[string][]string с линейной проверкой пути;
+// только для сравнения в бенчмарках
+func mapSimplePaths(ctx context.Context, f *Farm, found func()) [][]string {
+	ans := [][]string{}
+	path := []string{f.Start}
+	steps, stopped := 0, false
+	var dfs func(string)
+	dfs = func(cur string) {
+		steps++
+		if stopped || (steps%1024 == 0 && ctx.Err() != nil) {
+			stopped = true
+			return
+		}
+		if cur == f.End {
+			ans = append(ans, append([]string{}, path...))
+			if found != nil {
+				found()
+			}
+			return
+		}
+		for _, nb := range f.Links[cur] {
+			if !Contains(path, nb) {
+				path = append(path, nb)
+				dfs(nb)
+				path = path[:len(path)-1]
+			}
+		}
+	}
+	dfs(f.Start)
+	return ans
+}
+
+// stepBudget — контекст, который "отменяется" после checks вызовов Err();
+// обход проверяет ctx раз в 1024 шага, так что бюджет — около checks*1024 шагов
+type stepBudget struct {
+	context.Context
+	checks int
+}
+
+func (c *stepBudget) Err() error {
+	if c.checks--; c.checks < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func budget(checks int) context.Context {
+	return &stepBudget{Context: context.Background(), checks: checks}
+}
+
+func TestSimplePathsMatchesMapSearch(t *testing.T) {
+	f := genFarm(12, 20, 1)
+	want := mapSimplePaths(context.Background(), f, nil)
+	g := NewIndexedGraph(f)
+	got, err := g.SimplePaths(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d paths, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if fmt.Sprint(g.PathNames(got[i])) != fmt.Sprint(want[i]) {
+			t.Fatalf("path %d: got %v, want %v", i, g.PathNames(got[i]), want[i])
+		}
+	}
+}
+
+func TestDisjointGroups(t *testing.T) {
+	// start-a-end, start-b-end, start-a-b-end
+	f := &Farm{
+		Start: "s", End: "e",
+		Rooms: []Room{{Name: "s"}, {Name: "a"}, {Name: "b"}, {Name: "e"}},
+		Links: map[string][]string{
+			"s": {"a", "b"}, "a": {"s", "e", "b"}, "b": {"s", "e", "a"}, "e": {"a", "b"},
+		},
+	}
+	g := NewIndexedGraph(f)
+	paths, _ := g.SimplePaths(context.Background(), nil)
+	groups, err := g.DisjointGroups(context.Background(), paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != len(paths) {
+		t.Fatalf("got %d groups for %d paths", len(groups), len(paths))
+	}
+	if got := fmt.Sprint(g.PathsNames(groups[0])); got != "[[a e] [b e]]" {
+		t.Fatalf("first group = %s", got)
+	}
+}
+
+const (
+	benchRooms  = 10000
+	benchLinks  = 50000
+	benchChecks = 10 // ~10k шагов обхода
+)
+
+func BenchmarkNewIndexedGraph(b *testing.B) {
+	f := genFarm(benchRooms, benchLinks, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewIndexedGraph(f)
+	}
+}
+
+// все простые пути на такой ферме не перебрать, поэтому обход ограничен
+// бюджетом шагов; сравнивается стоимость шага, когда пути длинные
+func BenchmarkSimplePathsIndexed(b *testing.B) {
+	f := genFarm(benchRooms, benchLinks, 1)
+	g := NewIndexedGraph(f)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.SimplePaths(budget(benchChecks), nil)
+	}
+}
+
+func BenchmarkSimplePathsMap(b *testing.B) {
+	f := genFarm(benchRooms, benchLinks, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mapSimplePaths(budget(benchChecks), f, nil)
+	}
+}
+
+func BenchmarkDisjointGroups(b *testing.B) {
+	f := genFarm(benchRooms, benchLinks, 1)
+	g := NewIndexedGraph(f)
+	paths, _ := g.SimplePaths(budget(2000), nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.DisjointGroups(context.Background(), paths)
+	}
+}
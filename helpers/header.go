@@ -10,14 +10,6 @@ type Ant struct {
 	PrevY   int
 }
 
-var (
-	N         int
-	Err       error
-	RoomNames []string
-	Matrix    [][]string
-	BestGroup [][]string
-)
-
 type VizAnt struct {
 	So    []Ant
 	Path  [][]int
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+)
+
+// Itinerary — маршрут одного муравья по ходам
+type Itinerary struct {
+	Ant       string
+	Path      []string // комнаты по порядку, без start, с end
+	Departure int      // ход, на котором муравей покинул start (с 1)
+	Arrival   int      // ход, на котором он пришёл в end
+	Waits     int      // ходы в пути без движения
+}
+
+// Travel — сколько ходов муравей провёл в пути, включая ожидание
+func (it Itinerary) Travel() int { return it.Arrival - it.Departure + 1 }
+
+// Itineraries — маршруты всех муравьёв из ходов ("L1-a"), по номеру муравья
+func Itineraries(turns [][]string) []Itinerary {
+	byAnt := map[string]*Itinerary{}
+	for t, turn := range turns {
+		for _, move := range turn {
+			ant, room, ok := strings.Cut(move, "-")
+			if !ok {
+				continue
+			}
+			it, exists := byAnt[ant]
+			if !exists {
+				it = &Itinerary{Ant: ant, Departure: t + 1}
+				byAnt[ant] = it
+			}
+			it.Path = append(it.Path, room)
+			it.Arrival = t + 1
+		}
+	}
+
+	out := make([]Itinerary, 0, len(byAnt))
+	for _, it := range byAnt {
+		it.Waits = it.Travel() - len(it.Path)
+		out = append(out, *it)
+	}
+	sort.Slice(out, func(i, j int) bool { return antNumber(out[i].Ant) < antNumber(out[j].Ant) })
+	return out
+}
+
+// TravelStats — время в пути по всем муравьям
+type TravelStats struct {
+	Mean   float64
+	Median float64
+	Max    int
+}
+
+// ItineraryStats — среднее, медиана и максимум Travel
+func ItineraryStats(its []Itinerary) TravelStats {
+	if len(its) == 0 {
+		return TravelStats{}
+	}
+	travel := make([]int, len(its))
+	sum := 0
+	for i, it := range its {
+		travel[i] = it.Travel()
+		sum += travel[i]
+	}
+	sort.Ints(travel)
+	s := TravelStats{Mean: float64(sum) / float64(len(travel)), Max: travel[len(travel)-1]}
+	if mid := len(travel) / 2; len(travel)%2 == 1 {
+		s.Median = float64(travel[mid])
+	} else {
+		s.Median = float64(travel[mid-1]+travel[mid]) / 2
+	}
+	return s
+}
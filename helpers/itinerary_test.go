@@ -0,0 +1,22 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestItineraries(t *testing.T) {
+	turns := SplitTurns([]string{"L1-a L2-b", "L1-end", "L2-c L3-a", "L2-end L3-end"})
+	its := Itineraries(turns)
+	want := []Itinerary{
+		{Ant: "L1", Path: []string{"a", "end"}, Departure: 1, Arrival: 2},
+		{Ant: "L2", Path: []string{"b", "c", "end"}, Departure: 1, Arrival: 4, Waits: 1},
+		{Ant: "L3", Path: []string{"a", "end"}, Departure: 3, Arrival: 4},
+	}
+	if !reflect.DeepEqual(its, want) {
+		t.Fatalf("itineraries = %+v", its)
+	}
+	if s := ItineraryStats(its); s != (TravelStats{Mean: 8.0 / 3, Median: 2, Max: 4}) {
+		t.Fatalf("stats = %+v", s)
+	}
+}
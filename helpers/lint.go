@@ -0,0 +1,175 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Правила линтера: валидные, но подозрительные карты.
+const (
+	RuleUnreachable      = "unreachable"
+	RuleDeadEnd          = "dead-end"
+	RuleSameCoords       = "same-coords"
+	RuleBadName          = "bad-name"
+	RuleIsolated         = "isolated-component"
+	RuleUnknownDirective = "unknown-directive"
+	RuleLinkBeforeRooms  = "link-before-rooms"
+)
+
+// LintRules — все правила с описаниями (для справки и проверки -disable).
+var LintRules = map[string]string{
+	RuleUnreachable:      "room cannot be reached from start",
+	RuleDeadEnd:          "room with a single tunnel, no path can pass through it",
+	RuleSameCoords:       "several rooms share the same coordinates",
+	RuleBadName:          "room name starts with L or # and breaks the move format",
+	RuleIsolated:         "group of rooms connected to neither start nor end",
+	RuleUnknownDirective: "## directive other than ##start/##end is ignored",
+	RuleLinkBeforeRooms:  "link is defined before all rooms are declared",
+}
+
+type LintWarning struct {
+	Rule    string
+	Line    int // 0 — предупреждение относится ко всей карте
+	Message string
+}
+
+func (w LintWarning) String() string {
+	if w.Line > 0 {
+		return fmt.Sprintf("%d: %s: %s", w.Line, w.Rule, w.Message)
+	}
+	return fmt.Sprintf("%s: %s", w.Rule, w.Message)
+}
+
+// LintMap — ищет подозрительные места в карте. Жёсткие ошибки формата
+// должен отлавливать парсер; здесь — только предупреждения.
+// disabled — правила, которые нужно пропустить.
+func LintMap(text string, disabled map[string]bool) ([]LintWarning, error) {
+	m, err := ParseMapFile(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var warns []LintWarning
+	add := func(rule string, line int, format string, args ...any) {
+		if !disabled[rule] {
+			warns = append(warns, LintWarning{Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	// построчные правила
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "##start" || line == "##end":
+		case strings.HasPrefix(line, "##"):
+			add(RuleUnknownDirective, i+1, "unknown directive %s is ignored", line)
+		case strings.HasPrefix(line, "#") && looksLikeRoom(line):
+			add(RuleBadName, i+1, "%q looks like a room whose name starts with #, it is read as a comment", line)
+		}
+	}
+
+	lastRoomLine := 0
+	for _, r := range m.Rooms {
+		lastRoomLine = max(lastRoomLine, r.Line)
+		if strings.HasPrefix(r.Name, "L") {
+			add(RuleBadName, r.Line, "room %s starts with L and is ambiguous with ant names in moves", r.Name)
+		}
+	}
+	for _, l := range m.Links {
+		if l.Line < lastRoomLine {
+			add(RuleLinkBeforeRooms, l.Line, "link %s-%s is defined before room on line %d", l.From, l.To, lastRoomLine)
+			break
+		}
+	}
+
+	// координаты
+	byCoord := map[[2]int][]MapRoom{}
+	for _, r := range m.Rooms {
+		key := [2]int{r.X, r.Y}
+		byCoord[key] = append(byCoord[key], r)
+	}
+	for _, r := range m.Rooms {
+		same := byCoord[[2]int{r.X, r.Y}]
+		if len(same) > 1 && same[0].Name == r.Name {
+			names := make([]string, len(same))
+			for i, s := range same {
+				names[i] = s.Name
+			}
+			add(RuleSameCoords, same[1].Line, "rooms %s share coordinates %d %d", strings.Join(names, ", "), r.X, r.Y)
+		}
+	}
+
+	// граф
+	adj := map[string][]string{}
+	for _, r := range m.Rooms {
+		adj[r.Name] = nil
+	}
+	for _, l := range m.Links {
+		adj[l.From] = append(adj[l.From], l.To)
+		adj[l.To] = append(adj[l.To], l.From)
+	}
+
+	comp := map[string]int{}
+	var comps [][]string
+	for _, r := range m.Rooms {
+		if _, ok := comp[r.Name]; ok {
+			continue
+		}
+		id := len(comps)
+		queue := []string{r.Name}
+		comp[r.Name] = id
+		for k := 0; k < len(queue); k++ {
+			for _, nb := range adj[queue[k]] {
+				if _, ok := comp[nb]; !ok {
+					comp[nb] = id
+					queue = append(queue, nb)
+				}
+			}
+		}
+		comps = append(comps, queue)
+	}
+
+	startComp, hasStart := comp[m.Start]
+	endComp, hasEnd := comp[m.End]
+	for id, rooms := range comps {
+		if (hasStart && id == startComp) || (hasEnd && id == endComp) {
+			continue
+		}
+		sort.Strings(rooms)
+		add(RuleIsolated, 0, "rooms %s are connected to neither start nor end", strings.Join(rooms, ", "))
+	}
+	if hasStart && hasEnd && startComp != endComp {
+		for _, r := range m.Rooms {
+			if comp[r.Name] == endComp {
+				add(RuleUnreachable, r.Line, "room %s cannot be reached from start", r.Name)
+			}
+		}
+	}
+
+	for _, r := range m.Rooms {
+		if r.Name != m.Start && r.Name != m.End && len(adj[r.Name]) == 1 {
+			add(RuleDeadEnd, r.Line, "room %s is a dead end (only tunnel to %s)", r.Name, adj[r.Name][0])
+		}
+	}
+
+	sort.SliceStable(warns, func(i, j int) bool {
+		if warns[i].Line == 0 || warns[j].Line == 0 {
+			return warns[i].Line != 0 && warns[j].Line == 0
+		}
+		return warns[i].Line < warns[j].Line
+	})
+	return warns, nil
+}
+
+func looksLikeRoom(line string) bool {
+	parts := strings.Fields(line)
+	if len(parts) != 3 || len(parts[0]) < 2 {
+		return false
+	}
+	_, errX := strconv.Atoi(parts[1])
+	_, errY := strconv.Atoi(parts[2])
+	return errX == nil && errY == nil
+}
@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	lintRooms = "1\n##start\ns 0 0\nm 1 0\n##end\ne 2 0\n"
+	lintLinks = "s-m\nm-e\ns-e\n"
+)
+
+func TestLintRules(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		line int
+	}{
+		RuleUnreachable:      {"1\n##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\ns-a\na-b\nb-s\n", 7},
+		RuleDeadEnd:          {lintRooms + "d 3 3\n" + lintLinks + "m-d\n", 7},
+		RuleSameCoords:       {lintRooms + "x 1 0\n" + lintLinks + "x-s\nx-e\n", 7},
+		RuleBadName:          {lintRooms + "Lx 3 3\n" + lintLinks + "Lx-s\nLx-e\n", 7},
+		RuleIsolated:         {lintRooms + "p 5 5\nq 6 6\n" + lintLinks + "p-q\n", 0},
+		RuleUnknownDirective: {"1\n##weird\n" + lintRooms[2:] + lintLinks, 2},
+		RuleLinkBeforeRooms:  {"1\n##start\ns 0 0\n##end\ne 2 0\ns-e\nm 1 0\nm-s\nm-e\n", 6},
+	}
+	for rule := range LintRules {
+		if _, ok := tests[rule]; !ok {
+			t.Errorf("no test for rule %s", rule)
+		}
+	}
+
+	for rule, tt := range tests {
+		warns, err := LintMap(tt.text, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", rule, err)
+		}
+		found := false
+		for _, w := range warns {
+			found = found || (w.Rule == rule && w.Line == tt.line)
+		}
+		if !found {
+			t.Errorf("%s: no warning on line %d, got %v", rule, tt.line, warns)
+		}
+
+		// отключённое правило молчит, остальные не меняются
+		disabled, err := LintMap(tt.text, map[string]bool{rule: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		others := 0
+		for _, w := range warns {
+			if w.Rule != rule {
+				others++
+			}
+		}
+		for _, w := range disabled {
+			if w.Rule == rule {
+				t.Errorf("%s: reported while disabled: %v", rule, w)
+			}
+		}
+		if len(disabled) != others {
+			t.Errorf("%s: disabling changed other warnings: %v -> %v", rule, warns, disabled)
+		}
+	}
+
+	if warns, err := LintMap(lintRooms+lintLinks, nil); err != nil || len(warns) != 0 {
+		t.Errorf("clean map: %v, %v", warns, err)
+	}
+}
+
+func TestLintExamples(t *testing.T) {
+	for name := range exampleFarms(t) {
+		raw, err := os.ReadFile(filepath.Join("../examples", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		warns, err := LintMap(string(raw), nil)
+		if err != nil || len(warns) != 0 {
+			t.Errorf("%s: %v %v", name, warns, err)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MapFile — редактируемое представление файла карты.
+// Комментарии привязаны к строке, перед которой стояли, и сохраняются при записи.
+type MapFile struct {
+	Head  []string // комментарии перед числом муравьёв
+	Ants  int
+	Start string
+	End   string
+	Rooms []MapRoom
+	Links []MapLink
+	Tail  []string // комментарии в конце файла
+}
+
+type MapRoom struct {
+	Name     string
+	X        int
+	Y        int
+	Line     int // номер строки в исходном файле (0 — добавлена программно)
+	Comments []string
+}
+
+type MapLink struct {
+	From     string
+	To       string
+	Line     int
+	Comments []string
+}
+
+// ParseMapFile — разбирает текст карты без проверки достижимости и т.п.,
+// только структура строк (муравьи, комнаты, связи, комментарии).
+func ParseMapFile(text string) (*MapFile, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	m := &MapFile{}
+	var comments []string
+	antsSeen := false
+	flag := "room"
+
+	for num, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		if line == "##start" || line == "##end" {
+			flag = line[2:]
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			comments = append(comments, line)
+			continue
+		}
+		if !antsSeen {
+			n, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number of ants: %s", line)
+			}
+			m.Ants = n
+			m.Head, comments = comments, nil
+			antsSeen = true
+			continue
+		}
+
+		parts := strings.Fields(line)
+		if len(parts) == 3 {
+			x, errX := strconv.Atoi(parts[1])
+			y, errY := strconv.Atoi(parts[2])
+			if errX != nil || errY != nil {
+				return nil, fmt.Errorf("invalid room line: %s", line)
+			}
+			m.Rooms = append(m.Rooms, MapRoom{Name: parts[0], X: x, Y: y, Line: num + 1, Comments: comments})
+			comments = nil
+			switch flag {
+			case "start":
+				m.Start = parts[0]
+			case "end":
+				m.End = parts[0]
+			}
+			flag = "room"
+			continue
+		}
+
+		if len(parts) == 1 && strings.Contains(line, "-") {
+			a, b := ParseLink(line)
+			if a == "" || b == "" {
+				return nil, fmt.Errorf("invalid link: %s", line)
+			}
+			m.Links = append(m.Links, MapLink{From: a, To: b, Line: num + 1, Comments: comments})
+			comments = nil
+			continue
+		}
+
+		return nil, fmt.Errorf("wrong format near: %s", line)
+	}
+	if !antsSeen {
+		return nil, fmt.Errorf("empty file")
+	}
+	m.Tail = comments
+	return m, nil
+}
+
+// String — текст карты в формате lem-in.
+func (m *MapFile) String() string {
+	var b strings.Builder
+	writeComments(&b, m.Head)
+	fmt.Fprintf(&b, "%d\n", m.Ants)
+	for _, r := range m.Rooms {
+		writeComments(&b, r.Comments)
+		if r.Name == m.Start {
+			b.WriteString("##start\n")
+		} else if r.Name == m.End {
+			b.WriteString("##end\n")
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", r.Name, r.X, r.Y)
+	}
+	for _, l := range m.Links {
+		writeComments(&b, l.Comments)
+		fmt.Fprintf(&b, "%s-%s\n", l.From, l.To)
+	}
+	writeComments(&b, m.Tail)
+	return b.String()
+}
+
+func writeComments(b *strings.Builder, comments []string) {
+	for _, c := range comments {
+		b.WriteString(c)
+		b.WriteByte('\n')
+	}
+}
+
+func (m *MapFile) roomIndex(name string) int {
+	for i, r := range m.Rooms {
+		if r.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MapFile) linkIndex(a, b string) int {
+	for i, l := range m.Links {
+		if (l.From == a && l.To == b) || (l.From == b && l.To == a) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MapFile) AddRoom(name string, x, y int) error {
+	if name == "" || strings.ContainsAny(name, " \t-") || strings.HasPrefix(name, "#") || strings.HasPrefix(name, "L") {
+		return fmt.Errorf("invalid room name %q", name)
+	}
+	if m.roomIndex(name) != -1 {
+		return fmt.Errorf("room %s already exists", name)
+	}
+	m.Rooms = append(m.Rooms, MapRoom{Name: name, X: x, Y: y})
+	return nil
+}
+
+// RemoveRoom — удаляет комнату вместе со всеми её связями.
+func (m *MapFile) RemoveRoom(name string) error {
+	i := m.roomIndex(name)
+	if i == -1 {
+		return fmt.Errorf("room %s is not defined", name)
+	}
+	m.Rooms = append(m.Rooms[:i], m.Rooms[i+1:]...)
+	links := m.Links[:0]
+	for _, l := range m.Links {
+		if l.From != name && l.To != name {
+			links = append(links, l)
+		}
+	}
+	m.Links = links
+	if m.Start == name {
+		m.Start = ""
+	}
+	if m.End == name {
+		m.End = ""
+	}
+	return nil
+}
+
+func (m *MapFile) MoveRoom(name string, x, y int) error {
+	i := m.roomIndex(name)
+	if i == -1 {
+		return fmt.Errorf("room %s is not defined", name)
+	}
+	m.Rooms[i].X, m.Rooms[i].Y = x, y
+	return nil
+}
+
+func (m *MapFile) AddLink(a, b string) error {
+	if a == b {
+		return fmt.Errorf("room %s cannot be linked to itself", a)
+	}
+	for _, name := range []string{a, b} {
+		if m.roomIndex(name) == -1 {
+			return fmt.Errorf("room %s is not defined", name)
+		}
+	}
+	if m.linkIndex(a, b) != -1 {
+		return fmt.Errorf("duplicate link %s-%s", a, b)
+	}
+	m.Links = append(m.Links, MapLink{From: a, To: b})
+	return nil
+}
+
+func (m *MapFile) RemoveLink(a, b string) error {
+	i := m.linkIndex(a, b)
+	if i == -1 {
+		return fmt.Errorf("link %s-%s does not exist", a, b)
+	}
+	m.Links = append(m.Links[:i], m.Links[i+1:]...)
+	return nil
+}
+
+// SetRole — помечает комнату как "start" или "end".
+func (m *MapFile) SetRole(role, name string) error {
+	if m.roomIndex(name) == -1 {
+		return fmt.Errorf("room %s is not defined", name)
+	}
+	switch role {
+	case "start":
+		m.Start = name
+		if m.End == name {
+			m.End = ""
+		}
+	case "end":
+		m.End = name
+		if m.Start == name {
+			m.Start = ""
+		}
+	default:
+		return fmt.Errorf("unknown role %q", role)
+	}
+	return nil
+}
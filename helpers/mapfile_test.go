@@ -0,0 +1,26 @@
+package helpers
+
+import "testing"
+
+func TestMapFileRoundTrip(t *testing.T) {
+	text := "3\n# rooms\n##start\na 0 0\n##end\nb 4 0\n# middle\nc 2 1\na-c\n# shortcut\na-b\nc-b\n# end of file\n"
+	m, err := ParseMapFile(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.String(); got != text {
+		t.Fatalf("round trip:\ngot  %q\nwant %q", got, text)
+	}
+
+	// комментарии удалённых строк уходят вместе с ними, остальные остаются на месте
+	if err := m.RemoveLink("b", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MoveRoom("c", 3, 3); err != nil {
+		t.Fatal(err)
+	}
+	want := "3\n# rooms\n##start\na 0 0\n##end\nb 4 0\n# middle\nc 3 3\na-c\nc-b\n# end of file\n"
+	if got := m.String(); got != want {
+		t.Fatalf("after edit:\ngot  %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package helpers
+
+import "fmt"
+
+// SimulateMoves — ходы для группы путей без ожиданий (пути без start, с end).
+// Муравьи распределяются по путям как в NewPlan; на каждом ходу все идущие
+// делают шаг, затем из start выходит по муравью на каждый путь, где они ещё
+// остались. Симуляция идёт по id комнат, имена нужны только в ходах "Lx-room".
+func SimulateMoves(f *Farm, paths [][]string) [][]string {
+	g := NewIndexedGraph(f)
+	ids := make([][]int, len(paths))
+	for i, p := range paths {
+		ids[i] = make([]int, len(p))
+		for j, name := range p {
+			ids[i][j] = g.IDs[name]
+		}
+	}
+	return g.simulate(ids, f.Ants)
+}
+
+// PlanMoves — ходы решения: готовое расписание стратегии (с ожиданиями)
+// или симуляция по группе путей
+func PlanMoves(f *Farm, plan *Plan) [][]string {
+	if plan.Moves != nil {
+		return plan.Moves
+	}
+	return SimulateMoves(f, plan.Paths)
+}
+
+// simulate — SimulateMoves по путям из id
+func (g *IndexedGraph) simulate(paths [][]int, n int) [][]string {
+	left := AntHeights(CombinedHeights(paths, n), PathHeights(paths))
+	type ant struct {
+		num  int
+		path []int
+		pos  int // индекс следующей комнаты в path
+	}
+	var ants []ant
+	counter := 0
+	depart := func() {
+		for i := 0; i < len(left) && n > 0; i++ {
+			if left[i] > 0 {
+				counter++
+				ants = append(ants, ant{num: counter, path: paths[i]})
+				n--
+				left[i]--
+			}
+		}
+	}
+
+	turns := [][]string{}
+	for depart(); len(ants) > 0; depart() {
+		turn := make([]string, 0, len(ants))
+		walking := ants[:0]
+		for _, a := range ants {
+			turn = append(turn, fmt.Sprintf("L%d-%s", a.num, g.Names[a.path[a.pos]]))
+			if a.pos++; a.pos < len(a.path) {
+				walking = append(walking, a)
+			}
+		}
+		ants = walking
+		turns = append(turns, turn)
+	}
+	return turns
+}
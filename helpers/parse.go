@@ -0,0 +1,186 @@
+package helpers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxLineLength — предел длины строки, если ParseOptions.MaxLineLength не задан
+const DefaultMaxLineLength = 1 << 20
+
+// progressEvery — как часто (в строках) вызывается ParseOptions.Progress
+const progressEvery = 1 << 16
+
+// ParseStats — сколько уже разобрано
+type ParseStats struct {
+	Lines int
+	Bytes int64
+	Rooms int
+	Links int
+}
+
+// ParseOptions — ограничения потокового парсера (0 — без ограничения) и отчёт о ходе разбора
+type ParseOptions struct {
+	MaxRooms      int
+	MaxLinks      int
+	MaxLineLength int              // 0 — DefaultMaxLineLength
+	Progress      func(ParseStats) // каждые 65536 строк и в конце разбора
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ParseFarm — потоковый разбор карты: строки читаются по одной и проверяются
+// сразу, файл целиком в памяти не держится.
+func ParseFarm(ctx context.Context, r io.Reader, opts ParseOptions) (*Farm, error) {
+	maxLine := opts.MaxLineLength
+	if maxLine <= 0 {
+		maxLine = DefaultMaxLineLength
+	}
+	cr := &countingReader{r: r}
+	sc := bufio.NewScanner(cr)
+	sc.Buffer(make([]byte, 0, min(maxLine, 64*1024)), maxLine)
+
+	f := &Farm{Links: map[string][]string{}}
+	stats := ParseStats{}
+	report := func() {
+		if opts.Progress != nil {
+			stats.Bytes = cr.n
+			opts.Progress(stats)
+		}
+	}
+	scanErr := func() error {
+		if errors.Is(sc.Err(), bufio.ErrTooLong) {
+			return fmt.Errorf("line %d is longer than %d bytes", stats.Lines+1, maxLine)
+		}
+		return sc.Err()
+	}
+
+	// первая строка — количество муравьёв
+	first := ""
+	if sc.Scan() {
+		first = sc.Text()
+		stats.Lines++
+	} else if err := scanErr(); err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid number of ants: %s", first)
+	}
+	if n <= 0 {
+		return nil, errors.New("Number of ants must be a positive integer")
+	}
+	f.Ants = n
+
+	flag := "room"
+	for sc.Scan() {
+		stats.Lines++
+		if stats.Lines%progressEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			report()
+		}
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+
+		// комментарии "#..." пропускаем, из директив "##..." важны только start и end
+		if strings.HasPrefix(line, "#") {
+			switch line {
+			case "##start":
+				flag = "start"
+			case "##end":
+				flag = "end"
+			}
+			continue
+		}
+
+		// описание комнаты: name X Y
+		parts := strings.Fields(line)
+		if len(parts) == 3 {
+			name := parts[0]
+			x, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid X coordinate for room %s", name)
+			}
+			y, err := strconv.Atoi(parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid Y coordinate for room %s", name)
+			}
+			if _, exists := f.Links[name]; exists {
+				return nil, fmt.Errorf("Room %s already exists", name)
+			}
+			if opts.MaxRooms > 0 && len(f.Rooms) >= opts.MaxRooms {
+				return nil, fmt.Errorf("line %d: too many rooms (limit %d)", stats.Lines, opts.MaxRooms)
+			}
+			f.Rooms = append(f.Rooms, Room{Name: name, X: x, Y: y})
+			f.Links[name] = []string{}
+			stats.Rooms++
+
+			if flag == "start" {
+				f.Start = name
+			} else if flag == "end" {
+				f.End = name
+			}
+			flag = "room"
+			continue
+		}
+
+		// описание связи: A-B
+		if strings.Contains(line, "-") && !strings.Contains(line, " ") {
+			a, b := ParseLink(line)
+			switch {
+			case a == "" || b == "":
+				return nil, fmt.Errorf("Invalid link format: %s", line)
+			case a == b:
+				return nil, fmt.Errorf("Invalid link: room %s cannot be linked to itself", a)
+			}
+			if _, ok := f.Links[a]; !ok {
+				return nil, fmt.Errorf("Invalid link: room %s is not defined", a)
+			}
+			if _, ok := f.Links[b]; !ok {
+				return nil, fmt.Errorf("Invalid link: room %s is not defined", b)
+			}
+			if Contains(f.Links[a], b) {
+				return nil, fmt.Errorf("Invalid link: duplicate link %s-%s", a, b)
+			}
+			if opts.MaxLinks > 0 && stats.Links >= opts.MaxLinks {
+				return nil, fmt.Errorf("line %d: too many links (limit %d)", stats.Lines, opts.MaxLinks)
+			}
+			f.Links[a] = append(f.Links[a], b)
+			f.Links[b] = append(f.Links[b], a)
+			stats.Links++
+			continue
+		}
+
+		return nil, fmt.Errorf("Wrong format in graph.txt near line: %s", line)
+	}
+	if err := scanErr(); err != nil {
+		return nil, err
+	}
+	report()
+
+	if f.Start == "" {
+		return nil, errors.New("Start room not defined")
+	}
+	if f.End == "" {
+		return nil, errors.New("End room not defined")
+	}
+	return f, nil
+}
@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const smallMap = `3
+#comment
+##start
+a 0 0
+##end
+b 4 0
+c 2 1
+a-c
+c-b
+a-b
+`
+
+func TestParseFarm(t *testing.T) {
+	f, err := ParseFarm(context.Background(), strings.NewReader(strings.ReplaceAll(smallMap, "\n", "\r\n")), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Ants != 3 || f.Start != "a" || f.End != "b" || len(f.Rooms) != 3 {
+		t.Fatalf("unexpected farm: %+v", f)
+	}
+	if got := strings.Join(f.Links["a"], ","); got != "c,b" {
+		t.Fatalf("links of a = %s", got)
+	}
+	if f.Rooms[2] != (Room{Name: "c", X: 2, Y: 1}) {
+		t.Fatalf("room c = %+v", f.Rooms[2])
+	}
+}
+
+func TestParseFarmErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts ParseOptions
+		want string
+	}{
+		{"ants", "x\n", ParseOptions{}, "Invalid number of ants: x"},
+		{"duplicate room", smallMap + "c 1 1\n", ParseOptions{}, "Room c already exists"},
+		{"duplicate link", smallMap + "b-c\n", ParseOptions{}, "duplicate link b-c"},
+		{"undefined room", smallMap + "a-z\n", ParseOptions{}, "room z is not defined"},
+		{"no end", "1\n##start\na 0 0\n", ParseOptions{}, "End room not defined"},
+		{"max rooms", smallMap, ParseOptions{MaxRooms: 2}, "too many rooms (limit 2)"},
+		{"max links", smallMap, ParseOptions{MaxLinks: 2}, "line 10: too many links (limit 2)"},
+		{"max line", smallMap, ParseOptions{MaxLineLength: 6}, "line 2 is longer than 6 bytes"},
+	}
+	for _, tt := range tests {
+		_, err := ParseFarm(context.Background(), strings.NewReader(tt.text), tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParseFarmProgress(t *testing.T) {
+	var last ParseStats
+	opts := ParseOptions{Progress: func(s ParseStats) { last = s }}
+	if _, err := ParseFarm(context.Background(), strings.NewReader(smallMap), opts); err != nil {
+		t.Fatal(err)
+	}
+	want := ParseStats{Lines: 10, Bytes: int64(len(smallMap)), Rooms: 3, Links: 3}
+	if last != want {
+		t.Fatalf("progress = %+v, want %+v", last, want)
+	}
+}
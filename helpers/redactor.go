@@ -0,0 +1,199 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RedactorGraph — формат файла, который сохраняет graph-redactor (SaveFile).
+type RedactorGraph struct {
+	X0       float64          `json:"x0"`
+	Y0       float64          `json:"y0"`
+	Vertices []RedactorVertex `json:"vertices"`
+	Edges    []RedactorEdge   `json:"edges"`
+	Texts    []RedactorText   `json:"texts"`
+}
+
+type RedactorVertex struct {
+	Name  string  `json:"name"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Color string  `json:"color"`
+}
+
+type RedactorEdge struct {
+	Vertex1 int `json:"vertex1"`
+	Vertex2 int `json:"vertex2"`
+}
+
+type RedactorText struct {
+	Text string  `json:"text"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// цвета, которыми в редакторе помечают старт и конец (как в визуализаторе)
+var (
+	StartColors = []string{"green", "#4caf50", "#0f0", "#00ff00", "#008000"}
+	EndColors   = []string{"red", "#f44336", "#f00", "#ff0000"}
+)
+
+// ConvertRedactor — превращает сохранённый JSON редактора в карту lem-in.
+// Старт/конец берутся из текстовых меток ("start", "end", "start: A")
+// или из цвета вершины, количество муравьёв — из метки ("ants: 10" или просто число).
+// ants > 0 переопределяет значение из меток. Готовая карта проверяется ParseFarm.
+func ConvertRedactor(data []byte, ants int) (string, error) {
+	var g RedactorGraph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return "", fmt.Errorf("invalid editor JSON: %w", err)
+	}
+	if len(g.Vertices) == 0 {
+		return "", fmt.Errorf("editor graph has no vertices")
+	}
+
+	names := make(map[string]int, len(g.Vertices))
+	for i, v := range g.Vertices {
+		name := strings.TrimSpace(v.Name)
+		if name == "" {
+			return "", fmt.Errorf("vertex %d has no name", i)
+		}
+		if strings.ContainsAny(name, " \t-") || strings.HasPrefix(name, "L") || strings.HasPrefix(name, "#") {
+			return "", fmt.Errorf("vertex name %q cannot be used as a room name", name)
+		}
+		if _, ok := names[name]; ok {
+			return "", fmt.Errorf("duplicate vertex name %q", name)
+		}
+		names[name] = i
+		g.Vertices[i].Name = name
+	}
+
+	start, end, labelAnts := -1, -1, 0
+	setRole := func(role *int, idx int, label string) error {
+		if *role != -1 && *role != idx {
+			return fmt.Errorf("%s marked more than once", label)
+		}
+		*role = idx
+		return nil
+	}
+
+	for _, t := range g.Texts {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(t.Text), ":")
+		if !hasValue {
+			key, value, hasValue = strings.Cut(key, "=")
+		}
+		key = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(key), "##"))
+		value = strings.TrimSpace(value)
+
+		if n, err := strconv.Atoi(key); err == nil && !hasValue {
+			labelAnts = n
+			continue
+		}
+		switch key {
+		case "ants":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid ants label: %q", t.Text)
+			}
+			labelAnts = n
+		case "start", "end":
+			idx := -1
+			if hasValue {
+				i, ok := names[value]
+				if !ok {
+					return "", fmt.Errorf("label %q references unknown vertex", t.Text)
+				}
+				idx = i
+			} else {
+				idx = nearestVertex(g.Vertices, t.X, t.Y)
+			}
+			role := &start
+			if key == "end" {
+				role = &end
+			}
+			if err := setRole(role, idx, key); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	for i, v := range g.Vertices {
+		color := strings.ToLower(strings.TrimSpace(v.Color))
+		if color == "" {
+			continue
+		}
+		if Contains(StartColors, color) {
+			if err := setRole(&start, i, "start"); err != nil {
+				return "", err
+			}
+		}
+		if Contains(EndColors, color) {
+			if err := setRole(&end, i, "end"); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if ants <= 0 {
+		ants = labelAnts
+	}
+	if ants <= 0 {
+		return "", fmt.Errorf("number of ants not specified (add an \"ants: N\" label)")
+	}
+	if start == -1 {
+		return "", fmt.Errorf("start room not marked")
+	}
+	if end == -1 {
+		return "", fmt.Errorf("end room not marked")
+	}
+	if start == end {
+		return "", fmt.Errorf("start and end are the same vertex %q", g.Vertices[start].Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n", ants)
+	for i, v := range g.Vertices {
+		if i == start {
+			b.WriteString("##start\n")
+		} else if i == end {
+			b.WriteString("##end\n")
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", v.Name, int(math.Round(v.X)), int(math.Round(v.Y)))
+	}
+
+	seen := map[[2]int]bool{}
+	for _, e := range g.Edges {
+		if e.Vertex1 < 0 || e.Vertex1 >= len(g.Vertices) || e.Vertex2 < 0 || e.Vertex2 >= len(g.Vertices) {
+			return "", fmt.Errorf("edge %d-%d references unknown vertex", e.Vertex1, e.Vertex2)
+		}
+		if e.Vertex1 == e.Vertex2 {
+			return "", fmt.Errorf("vertex %q cannot be linked to itself", g.Vertices[e.Vertex1].Name)
+		}
+		key := [2]int{min(e.Vertex1, e.Vertex2), max(e.Vertex1, e.Vertex2)}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fmt.Fprintf(&b, "%s-%s\n", g.Vertices[e.Vertex1].Name, g.Vertices[e.Vertex2].Name)
+	}
+
+	// результат проверяется тем же парсером, что и обычные карты
+	if _, err := ParseFarm(context.Background(), strings.NewReader(b.String()), ParseOptions{}); err != nil {
+		return "", fmt.Errorf("converted map is invalid: %w", err)
+	}
+	return b.String(), nil
+}
+
+func nearestVertex(vertices []RedactorVertex, x, y float64) int {
+	best, bestDist := -1, math.Inf(1)
+	for i, v := range vertices {
+		d := math.Hypot(v.X-x, v.Y-y)
+		if d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
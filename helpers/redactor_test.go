@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// redactorJSON — сохранённый граф редактора: s (зелёная) - m - e (красная), 4 муравья
+const redactorJSON = `{
+	"x0": 0, "y0": 0,
+	"vertices": [
+		{"name": "s", "x": 10, "y": 20, "color": "green"},
+		{"name": "m", "x": 30.4, "y": 20, "color": ""},
+		{"name": "e", "x": 50, "y": 19.6, "color": "red"}
+	],
+	"edges": [{"vertex1": 0, "vertex2": 1}, {"vertex1": 1, "vertex2": 2}, {"vertex1": 2, "vertex2": 1}],
+	"texts": [{"text": "ants: 4", "x": 0, "y": 0}]
+}`
+
+func TestConvertRedactor(t *testing.T) {
+	text, err := ConvertRedactor([]byte(redactorJSON), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "4\n##start\ns 10 20\nm 30 20\n##end\ne 50 20\ns-m\nm-e\n"
+	if text != want {
+		t.Fatalf("got %q, want %q", text, want)
+	}
+	if _, err := ParseFarm(context.Background(), strings.NewReader(text), ParseOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := ConvertRedactor([]byte(redactorJSON), 7); !strings.HasPrefix(text, "7\n") {
+		t.Fatalf("ants override ignored: %q", text)
+	}
+}
+
+func TestConvertRedactorErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"missing end", strings.Replace(redactorJSON, `"red"`, `""`, 1), "end room not marked"},
+		{"duplicate room", strings.Replace(redactorJSON, `"name": "m"`, `"name": "s"`, 1), `duplicate vertex name "s"`},
+		{"invalid JSON", redactorJSON[:20], "invalid editor JSON"},
+	}
+	for _, tt := range tests {
+		_, err := ConvertRedactor([]byte(tt.json), 0)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
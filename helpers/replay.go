@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AntPos — положение муравья после хода: Room — где он сейчас, Prev — где был до этого хода.
+type AntPos struct {
+	Name string
+	Prev string
+	Room string
+}
+
+// AntsAt — положение всех муравьёв, покинувших старт, после k ходов.
+// Остальные муравьи всё ещё в start.
+func AntsAt(turns [][]string, start string, k int) []AntPos {
+	pos := map[string]*AntPos{}
+	for t := 0; t < k && t < len(turns); t++ {
+		for _, p := range pos {
+			p.Prev = p.Room
+		}
+		for _, move := range turns[t] {
+			ant, room, ok := strings.Cut(move, "-")
+			if !ok {
+				continue
+			}
+			p, exists := pos[ant]
+			if !exists {
+				p = &AntPos{Name: ant, Room: start}
+				pos[ant] = p
+			}
+			p.Prev = p.Room
+			p.Room = room
+		}
+	}
+
+	ans := make([]AntPos, 0, len(pos))
+	for _, p := range pos {
+		ans = append(ans, *p)
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		return antNumber(ans[i].Name) < antNumber(ans[j].Name)
+	})
+	return ans
+}
+
+func antNumber(name string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(name, "L"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// SplitTurns — строки ходов ("L1-a L2-b") в списки перемещений
+func SplitTurns(lines []string) [][]string {
+	turns := make([][]string, len(lines))
+	for i, line := range lines {
+		turns[i] = strings.Fields(line)
+	}
+	return turns
+}
@@ -0,0 +1,210 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultSolver — стратегия по умолчанию (исходный перебор путей)
+const DefaultSolver = "dfs"
+
+// Plan — решение фермы: пути и сколько муравьёв идёт по каждому
+type Plan struct {
+	Solver string
+	Paths  [][]string // без start, с end
+	Ants   []int      // муравьёв на каждом пути
+	Turns  int        // число ходов
+	// Moves — готовые ходы ("L1-a L2-b" по ходам), если стратегия строит
+	// расписание сама (с ожиданием); nil — ходы считаются по Paths и Ants
+	Moves [][]string
+}
+
+// Solver — стратегия выбора путей
+type Solver interface {
+	Solve(ctx context.Context, f *Farm) (*Plan, error)
+}
+
+// SolverInfo — описание зарегистрированной стратегии
+type SolverInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var solvers = struct {
+	sync.RWMutex
+	m map[string]registeredSolver
+}{m: map[string]registeredSolver{}}
+
+type registeredSolver struct {
+	info   SolverInfo
+	solver Solver
+}
+
+// RegisterSolver — добавляет стратегию; повторное имя — ошибка программиста
+func RegisterSolver(name, description string, s Solver) {
+	solvers.Lock()
+	defer solvers.Unlock()
+	if _, dup := solvers.m[name]; dup {
+		panic("helpers: solver " + name + " registered twice")
+	}
+	solvers.m[name] = registeredSolver{SolverInfo{name, description}, s}
+}
+
+// LookupSolver — стратегия по имени ("" — DefaultSolver)
+func LookupSolver(name string) (Solver, error) {
+	if name == "" {
+		name = DefaultSolver
+	}
+	solvers.RLock()
+	r, ok := solvers.m[name]
+	solvers.RUnlock()
+	if !ok {
+		names := []string{}
+		for _, info := range Solvers() {
+			names = append(names, info.Name)
+		}
+		return nil, fmt.Errorf("unknown solver %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return r.solver, nil
+}
+
+// Solvers — все стратегии, по имени
+func Solvers() []SolverInfo {
+	solvers.RLock()
+	defer solvers.RUnlock()
+	out := make([]SolverInfo, 0, len(solvers.m))
+	for _, r := range solvers.m {
+		out = append(out, r.info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// MaxReportedPaths — сколько найденных путей SolveError хранит по именам
+const MaxReportedPaths = 20
+
+// SolveError — решение прервано по таймауту или отмене; Found — сколько путей
+// найдено до этого, Paths — первые из них (от start до end), не больше MaxReportedPaths
+type SolveError struct {
+	Err   error
+	Found int
+	Paths [][]string
+}
+
+// newSolveError — имена только у первых MaxReportedPaths путей: на плотной карте
+// их миллионы, и отмена должна оставаться дешёвой
+func (g *IndexedGraph) newSolveError(err error, paths [][]int) *SolveError {
+	return &SolveError{Err: err, Found: len(paths), Paths: g.PathsNames(paths[:min(len(paths), MaxReportedPaths)])}
+}
+
+func (e *SolveError) Error() string {
+	return fmt.Sprintf("solve interrupted (%v) after finding %d paths", e.Err, e.Found)
+}
+
+func (e *SolveError) Unwrap() error { return e.Err }
+
+// SolveProgress — необязательный приёмник хода решения (стадия, найденные пути)
+type SolveProgress interface {
+	SetStage(stage string)
+	AddPath()
+}
+
+type solveProgressKey struct{}
+
+// WithSolveProgress — контекст, через который стратегии сообщают о ходе решения
+func WithSolveProgress(ctx context.Context, p SolveProgress) context.Context {
+	return context.WithValue(ctx, solveProgressKey{}, p)
+}
+
+// SolveProgressFrom — приёмник из контекста или nil
+func SolveProgressFrom(ctx context.Context) SolveProgress {
+	p, _ := ctx.Value(solveProgressKey{}).(SolveProgress)
+	return p
+}
+
+type noProgress struct{}
+
+func (noProgress) SetStage(string) {}
+func (noProgress) AddPath()        {}
+
+func progressOf(ctx context.Context) SolveProgress {
+	if p := SolveProgressFrom(ctx); p != nil {
+		return p
+	}
+	return noProgress{}
+}
+
+// CombinedHeights — длина каждого пути плюс муравьи, распределённые жадно:
+// очередной муравей идёт туда, где сумма сейчас минимальна.
+func CombinedHeights[T any](group [][]T, n int) []int {
+	heights := make([]int, len(group))
+	for i, k := range group {
+		heights[i] = len(k)
+	}
+	for i := 0; i < n; i++ {
+		if idx := IndexOfMin(heights); idx >= 0 {
+			heights[idx]++
+		}
+	}
+	return heights
+}
+
+// Height — высота группы (по первому пути) и высоты всех путей
+func Height[T any](group [][]T, n int) (int, []int) {
+	heights := CombinedHeights(group, n)
+	if len(heights) == 0 {
+		return 0, nil
+	}
+	return heights[0], heights
+}
+
+// BestGroupByHeight — группа с наименьшей высотой; nil, если групп нет
+func BestGroupByHeight[T any](groups [][][]T, n int) [][]T {
+	heights := make([]int, len(groups))
+	for i, group := range groups {
+		heights[i], _ = Height(group, n)
+	}
+	idx := IndexOfMin(heights)
+	if idx == -1 {
+		return nil
+	}
+	return groups[idx]
+}
+
+// PathHeights — длины путей группы
+func PathHeights[T any](group [][]T) []int {
+	heights := make([]int, len(group))
+	for i, path := range group {
+		heights[i] = len(path)
+	}
+	return heights
+}
+
+// AntHeights — муравьи на каждом пути: combined минус длины (combined меняется)
+func AntHeights(combined, heights []int) []int {
+	for i := range combined {
+		combined[i] -= heights[i]
+	}
+	return combined
+}
+
+// NewPlan — план для группы путей: жадное распределение n муравьёв и число ходов
+func NewPlan(solver string, paths [][]string, n int) *Plan {
+	ants := AntHeights(CombinedHeights(paths, n), PathHeights(paths))
+	return &Plan{Solver: solver, Paths: paths, Ants: ants, Turns: PlanTurns(paths, ants)}
+}
+
+// PlanTurns — число ходов: последний муравей пути i выходит на ходу ants[i]
+// и идёт len(paths[i]) ходов
+func PlanTurns(paths [][]string, ants []int) int {
+	turns := 0
+	for i, p := range paths {
+		if ants[i] > 0 {
+			turns = max(turns, len(p)+ants[i]-1)
+		}
+	}
+	return turns
+}
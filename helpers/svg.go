@@ -0,0 +1,193 @@
+package helpers
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+)
+
+// ViewRoom — комната с координатами из файла карты
+type ViewRoom struct {
+	Name string
+	X    int
+	Y    int
+}
+
+// FarmView — всё, что нужно для отрисовки фермы вне браузера.
+type FarmView struct {
+	Rooms    []ViewRoom
+	Links    [][2]string
+	Start    string
+	End      string
+	Ants     int
+	Paths    [][]string   // выбранная группа путей (без start, с end)
+	PathAnts []int        // муравьёв на каждом пути
+	Turns    [][]string   // ходы "Lx-room" по шагам
+	Heat     *Utilisation // не nil — комнаты и связи раскрашиваются по загрузке
+}
+
+// NewFarmView — данные для отрисовки решённой фермы: комнаты в порядке
+// объявления, ходы плана (с ожиданиями, если они есть); heat — добавить
+// загрузку комнат и связей
+func NewFarmView(f *Farm, plan *Plan, heat bool) *FarmView {
+	v := &FarmView{Start: f.Start, End: f.End, Ants: f.Ants, Paths: plan.Paths, PathAnts: plan.Ants}
+	for _, r := range f.Rooms {
+		v.Rooms = append(v.Rooms, ViewRoom{Name: r.Name, X: r.X, Y: r.Y})
+		for _, nb := range f.Links[r.Name] {
+			if r.Name < nb {
+				v.Links = append(v.Links, [2]string{r.Name, nb})
+			}
+		}
+	}
+	v.Turns = PlanMoves(f, plan)
+	if heat {
+		v.Heat = ComputeUtilisation(f, v.Turns)
+	}
+	return v
+}
+
+// Layout — пиксельные координаты комнат: масштаб по координатам карты, вписанный в size×size.
+func (v *FarmView) Layout(size, pad float64) (map[string][2]float64, float64, float64) {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, r := range v.Rooms {
+		minX, maxX = math.Min(minX, float64(r.X)), math.Max(maxX, float64(r.X))
+		minY, maxY = math.Min(minY, float64(r.Y)), math.Max(maxY, float64(r.Y))
+	}
+	spanX, spanY := math.Max(maxX-minX, 1), math.Max(maxY-minY, 1)
+	scale := (size - 2*pad) / math.Max(spanX, spanY)
+
+	pos := make(map[string][2]float64, len(v.Rooms))
+	for _, r := range v.Rooms {
+		pos[r.Name] = [2]float64{pad + (float64(r.X)-minX)*scale, pad + (float64(r.Y)-minY)*scale}
+	}
+	return pos, spanX*scale + 2*pad, spanY*scale + 2*pad
+}
+
+// PathLinks — связь "a|b" -> номер пути в выбранной группе
+func (v *FarmView) PathLinks() map[string]int {
+	ans := map[string]int{}
+	for i, p := range v.Paths {
+		prev := v.Start
+		for _, room := range p {
+			ans[linkKey(prev, room)] = i
+			prev = room
+		}
+	}
+	return ans
+}
+
+// heatByName — загрузка связей по linkKey и комнат по имени; пусто без Heat
+func (v *FarmView) heatByName() (map[string]LinkUsage, map[string]RoomUsage) {
+	links, rooms := map[string]LinkUsage{}, map[string]RoomUsage{}
+	if v.Heat == nil {
+		return links, rooms
+	}
+	for _, u := range v.Heat.Links {
+		links[linkKey(u.From, u.To)] = u
+	}
+	for _, u := range v.Heat.Rooms {
+		rooms[u.Room] = u
+	}
+	return links, rooms
+}
+
+func linkKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "|" + b
+}
+
+// RenderSVG — SVG фермы после turn ходов: комнаты, связи, пути выбранной группы и муравьи.
+func RenderSVG(w io.Writer, v *FarmView, turn int) error {
+	turn = max(0, min(turn, len(v.Turns)))
+	const roomR, antR = 14.0, 8.0
+	pos, width, height := v.Layout(800, 50)
+	height += 30 // строка со счётчиком ходов
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+
+	// связи: сначала обычные, поверх — пути выбранной группы
+	// (с Heat — все связи цветом и толщиной по загрузке)
+	onPath := v.PathLinks()
+	if v.Heat != nil {
+		onPath = map[string]int{}
+	}
+	linkHeat, roomHeat := v.heatByName()
+	b.WriteString(`<g id="links">` + "\n")
+	for _, l := range v.Links {
+		if u, ok := linkHeat[linkKey(l[0], l[1])]; ok {
+			a, c := pos[l[0]], pos[l[1]]
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f"><title>%s-%s: %d crossings, busy %.0f%%</title></line>`+"\n",
+				a[0], a[1], c[0], c[1], HeatColor(u.BusyRatio), 2+6*u.BusyRatio,
+				html.EscapeString(l[0]), html.EscapeString(l[1]), u.Crossings, 100*u.BusyRatio)
+			continue
+		}
+		if _, ok := onPath[linkKey(l[0], l[1])]; ok {
+			continue
+		}
+		a, c := pos[l[0]], pos[l[1]]
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#cccccc" stroke-width="2"/>`+"\n", a[0], a[1], c[0], c[1])
+	}
+	for _, l := range v.Links {
+		i, ok := onPath[linkKey(l[0], l[1])]
+		if !ok {
+			continue
+		}
+		a, c := pos[l[0]], pos[l[1]]
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="5" stroke-opacity="0.6"/>`+"\n",
+			a[0], a[1], c[0], c[1], AntPalette[i%len(AntPalette)])
+	}
+	b.WriteString("</g>\n")
+
+	// комнаты
+	b.WriteString(`<g id="rooms">` + "\n")
+	for _, r := range v.Rooms {
+		p := pos[r.Name]
+		fill := "#ffffff"
+		if r.Name == v.Start {
+			fill = "#4caf50"
+		} else if r.Name == v.End {
+			fill = "#f44336"
+		} else if u, ok := roomHeat[r.Name]; ok {
+			fill = HeatColor(u.BusyRatio)
+		}
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.0f" fill="%s" stroke="#000000" stroke-width="2"/>`+"\n", p[0], p[1], roomR, fill)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="12" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+			p[0], p[1]-roomR-8, html.EscapeString(r.Name))
+	}
+	b.WriteString("</g>\n")
+
+	// муравьи
+	ants := AntsAt(v.Turns, v.Start, turn)
+	count := map[string]int{v.Start: v.Ants - len(ants)}
+	b.WriteString(`<g id="ants">` + "\n")
+	for _, a := range ants {
+		count[a.Room]++
+		if a.Room == v.End {
+			continue
+		}
+		p := pos[a.Room]
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.0f" fill="%s"><title>%s</title></circle>`+"\n",
+			p[0], p[1], antR, AntColorHex(a.Name), html.EscapeString(a.Name))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="10" text-anchor="middle">%s</text>`+"\n",
+			p[0], p[1]+roomR+12, html.EscapeString(a.Name))
+	}
+	for _, name := range []string{v.Start, v.End} {
+		p := pos[name]
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="12" font-weight="bold" text-anchor="middle" dominant-baseline="middle">%d</text>`+"\n",
+			p[0], p[1], count[name])
+	}
+	b.WriteString("</g>\n")
+
+	fmt.Fprintf(&b, `<text x="10" y="%.0f" font-size="16" font-weight="bold">Turn %d/%d</text>`+"\n", height-10, turn, len(v.Turns))
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
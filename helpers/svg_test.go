@@ -0,0 +1,113 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// svgMap — коридор s - m<b - e, имя средней комнаты требует экранирования
+const svgMap = "2\n##start\ns 0 0\nm<b 1 0\n##end\ne 2 0\ns-m<b\nm<b-e\n"
+
+func svgView(t *testing.T) *FarmView {
+	t.Helper()
+	f, err := ParseFarm(context.Background(), strings.NewReader(svgMap), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver, err := LookupSolver(DefaultSolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := solver.Solve(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewFarmView(f, plan, false)
+}
+
+func renderSVG(t *testing.T, v *FarmView, turn int) string {
+	t.Helper()
+	var b strings.Builder
+	if err := RenderSVG(&b, v, turn); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestRenderSVGTurns(t *testing.T) {
+	v := svgView(t)
+	if len(v.Turns) != 3 {
+		t.Fatalf("turns = %v, want 3", v.Turns)
+	}
+	pos, _, _ := v.Layout(800, 50)
+	ant := func(name, room string) string {
+		p := pos[room]
+		return fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="8" fill="%s"><title>%s</title></circle>`,
+			p[0], p[1], AntColorHex(name), name)
+	}
+	tests := []struct {
+		turn  int
+		shown int
+		ants  []string // муравьи на поле (в end не рисуются)
+	}{
+		{-5, 0, nil},
+		{0, 0, nil},
+		{1, 1, []string{ant("L1", "m<b")}},
+		{2, 2, []string{ant("L2", "m<b")}},
+		{3, 3, nil},
+		{999, 3, nil},
+	}
+	for _, tt := range tests {
+		svg := renderSVG(t, v, tt.turn)
+		if want := fmt.Sprintf("Turn %d/3</text>", tt.shown); !strings.Contains(svg, want) {
+			t.Errorf("turn %d: no %q", tt.turn, want)
+		}
+		if got := strings.Count(svg, "<title>L"); got != len(tt.ants) {
+			t.Errorf("turn %d: %d ants drawn, want %d", tt.turn, got, len(tt.ants))
+		}
+		for _, a := range tt.ants {
+			if !strings.Contains(svg, a) {
+				t.Errorf("turn %d: no %s", tt.turn, a)
+			}
+		}
+	}
+	if renderSVG(t, v, -5) != renderSVG(t, v, 0) || renderSVG(t, v, 999) != renderSVG(t, v, 3) {
+		t.Error("out-of-range turn is not clamped")
+	}
+}
+
+func TestRenderSVGEscapesNames(t *testing.T) {
+	svg := renderSVG(t, svgView(t), 1)
+	if strings.Contains(svg, "m<b") {
+		t.Error("raw room name in SVG")
+	}
+	if !strings.Contains(svg, ">m&lt;b</text>") {
+		t.Error("escaped room label missing")
+	}
+}
+
+func TestNewFarmViewUsesPlanMoves(t *testing.T) {
+	f, err := ParseFarm(context.Background(), strings.NewReader(svgMap), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// расписание с ожиданием: L2 выходит на ход позже, чем в симуляции
+	moves := [][]string{{"L1-m<b"}, {"L1-e"}, {"L2-m<b"}, {"L2-e"}}
+	plan := &Plan{Solver: "exact", Paths: [][]string{{"m<b", "e"}}, Ants: []int{2}, Turns: 4, Moves: moves}
+	v := NewFarmView(f, plan, true)
+	if !reflect.DeepEqual(v.Turns, moves) {
+		t.Fatalf("turns = %v, want plan moves", v.Turns)
+	}
+	if !reflect.DeepEqual(v.PathAnts, []int{2}) || v.Heat == nil {
+		t.Fatalf("view = %+v", v)
+	}
+	want := []string{"s", "m<b", "e"}
+	for i, r := range v.Rooms {
+		if r.Name != want[i] {
+			t.Fatalf("rooms = %v, want declaration order %v", v.Rooms, want)
+		}
+	}
+}
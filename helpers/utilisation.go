@@ -0,0 +1,92 @@
+package helpers
+
+import "strings"
+
+// RoomUsage — сколько муравьёв прошло через комнату и сколько ходов она была занята
+type RoomUsage struct {
+	Room      string  `json:"room"`
+	Visits    int     `json:"visits"`    // муравьёв вошло (для start — вышло)
+	BusyTurns int     `json:"busyTurns"` // ходов, после которых в комнате был муравей
+	BusyRatio float64 `json:"busyRatio"` // BusyTurns / число ходов
+}
+
+// LinkUsage — сколько раз муравьи прошли по тоннелю и в скольких ходах он был занят
+type LinkUsage struct {
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Crossings int     `json:"crossings"`
+	BusyTurns int     `json:"busyTurns"`
+	BusyRatio float64 `json:"busyRatio"`
+}
+
+// Utilisation — загрузка комнат и тоннелей за всё решение
+type Utilisation struct {
+	Turns int         `json:"turns"`
+	Rooms []RoomUsage `json:"rooms"` // в порядке объявления
+	Links []LinkUsage `json:"links"` // From < To
+}
+
+// ComputeUtilisation — загрузка по ходам решения ("Lx-room"): комнаты и
+// тоннели, которые муравьи не используют, тоже попадают в результат с нулями
+func ComputeUtilisation(f *Farm, turns [][]string) *Utilisation {
+	u := &Utilisation{Turns: len(turns), Rooms: []RoomUsage{}, Links: []LinkUsage{}}
+	roomIdx := map[string]int{}
+	for _, r := range f.Rooms {
+		roomIdx[r.Name] = len(u.Rooms)
+		u.Rooms = append(u.Rooms, RoomUsage{Room: r.Name})
+	}
+	linkIdx := map[string]int{}
+	for _, r := range f.Rooms {
+		for _, nb := range f.Links[r.Name] {
+			if r.Name < nb {
+				linkIdx[linkKey(r.Name, nb)] = len(u.Links)
+				u.Links = append(u.Links, LinkUsage{From: r.Name, To: nb})
+			}
+		}
+	}
+
+	pos := map[string]string{} // муравей -> комната, только вышедшие из start
+	occupied := map[string]int{f.Start: f.Ants}
+	for _, turn := range turns {
+		used := map[int]bool{}
+		for _, move := range turn {
+			ant, room, ok := strings.Cut(move, "-")
+			if !ok {
+				continue
+			}
+			from, ok := pos[ant]
+			if !ok {
+				from = f.Start
+				u.Rooms[roomIdx[f.Start]].Visits++
+			}
+			pos[ant] = room
+			occupied[from]--
+			occupied[room]++
+			if i, ok := roomIdx[room]; ok {
+				u.Rooms[i].Visits++
+			}
+			if i, ok := linkIdx[linkKey(from, room)]; ok {
+				u.Links[i].Crossings++
+				used[i] = true
+			}
+		}
+		for i := range used {
+			u.Links[i].BusyTurns++
+		}
+		for room, n := range occupied {
+			if i, ok := roomIdx[room]; ok && n > 0 {
+				u.Rooms[i].BusyTurns++
+			}
+		}
+	}
+
+	if u.Turns > 0 {
+		for i := range u.Rooms {
+			u.Rooms[i].BusyRatio = float64(u.Rooms[i].BusyTurns) / float64(u.Turns)
+		}
+		for i := range u.Links {
+			u.Links[i].BusyRatio = float64(u.Links[i].BusyTurns) / float64(u.Turns)
+		}
+	}
+	return u
+}
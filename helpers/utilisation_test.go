@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestComputeUtilisation(t *testing.T) {
+	f, err := ParseFarm(context.Background(), strings.NewReader(smallMap), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a=start, b=end: два муравья напрямую, один через c
+	turns := SplitTurns([]string{"L1-b L2-c", "L2-b L3-b"})
+	u := ComputeUtilisation(f, turns)
+
+	want := map[string]RoomUsage{
+		"a": {Room: "a", Visits: 3, BusyTurns: 1, BusyRatio: 0.5},
+		"b": {Room: "b", Visits: 3, BusyTurns: 2, BusyRatio: 1},
+		"c": {Room: "c", Visits: 1, BusyTurns: 1, BusyRatio: 0.5},
+	}
+	for _, r := range u.Rooms {
+		if r != want[r.Room] {
+			t.Errorf("room %s = %+v, want %+v", r.Room, r, want[r.Room])
+		}
+	}
+	for _, l := range u.Links {
+		if l.From+"-"+l.To == "a-b" && (l.Crossings != 2 || l.BusyTurns != 2 || l.BusyRatio != 1) {
+			t.Errorf("link a-b = %+v", l)
+		}
+	}
+	if len(u.Links) != 3 {
+		t.Errorf("links = %+v", u.Links)
+	}
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"sort"
+)
+
+// LinkSuggestion — тоннель, которого нет на карте, и число ходов с ним
+type LinkSuggestion struct {
+	From, To string
+	Before   int // ходов на исходной карте
+	After    int // ходов с этим тоннелем
+}
+
+// Gain — на сколько ходов тоннель сокращает решение
+func (s LinkSuggestion) Gain() int { return s.Before - s.After }
+
+// CreatesPath — до тоннеля пути не было, и Gain не число ходов
+func (s LinkSuggestion) CreatesPath() bool { return s.Before == math.MaxInt && s.After != math.MaxInt }
+
+// CandidateLinks — все пары комнат без тоннеля на расстоянии не больше maxDist
+// (по координатам карты), в порядке объявления комнат
+func CandidateLinks(f *Farm, maxDist float64) [][2]string {
+	var out [][2]string
+	for i, a := range f.Rooms {
+		for _, b := range f.Rooms[i+1:] {
+			if math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y)) <= maxDist && !Contains(f.Links[a.Name], b.Name) {
+				out = append(out, [2]string{a.Name, b.Name})
+			}
+		}
+	}
+	return out
+}
+
+// WithLink — копия фермы с ещё одним тоннелем; исходная не меняется
+func (f *Farm) WithLink(a, b string) (*Farm, error) {
+	if _, ok := f.Links[a]; !ok {
+		return nil, fmt.Errorf("room %s is not defined", a)
+	}
+	if _, ok := f.Links[b]; !ok {
+		return nil, fmt.Errorf("room %s is not defined", b)
+	}
+	if a == b {
+		return nil, fmt.Errorf("room %s cannot be linked to itself", a)
+	}
+	if Contains(f.Links[a], b) {
+		return nil, fmt.Errorf("link %s-%s already exists", a, b)
+	}
+	g := *f
+	g.Group = nil
+	g.Links = maps.Clone(f.Links)
+	g.Links[a] = append(append([]string{}, f.Links[a]...), b)
+	g.Links[b] = append(append([]string{}, f.Links[b]...), a)
+	return &g, nil
+}
+
+// SuggestLinks — решает карту с каждым тоннелем-кандидатом по отдельности;
+// результат — от наибольшего выигрыша, при равном — в порядке кандидатов
+func SuggestLinks(ctx context.Context, f *Farm, candidates [][2]string, solver Solver) ([]LinkSuggestion, error) {
+	base, err := solver.Solve(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	before := base.Turns
+	if len(base.Paths) == 0 {
+		before = math.MaxInt // пути нет: любой тоннель, который его даёт, лучше
+	}
+
+	out := make([]LinkSuggestion, 0, len(candidates))
+	for _, c := range candidates {
+		g, err := f.WithLink(c[0], c[1])
+		if err != nil {
+			return nil, err
+		}
+		plan, err := solver.Solve(ctx, g)
+		if err != nil {
+			return nil, err
+		}
+		after := plan.Turns
+		if len(plan.Paths) == 0 {
+			after = math.MaxInt
+		}
+		out = append(out, LinkSuggestion{From: c[0], To: c[1], Before: before, After: after})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].After < out[j].After })
+	return out, nil
+}
@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSuggestLinks(t *testing.T) {
+	// единственный путь s-a-b-e; x — тупик рядом со start
+	text := "4\n##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\nx 1 1\ns-a\na-b\nb-e\ns-x\n"
+	f, err := ParseFarm(context.Background(), strings.NewReader(text), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := CandidateLinks(f, 2.5)
+	if len(candidates) != 5 {
+		t.Fatalf("candidates = %v", candidates)
+	}
+	solver, _ := LookupSolver("flow")
+	got, err := SuggestLinks(context.Background(), f, candidates, solver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// e-x даёт второй путь s-x-e: 6 ходов -> 4
+	if best := got[0]; best.From != "e" || best.To != "x" || best.Before != 6 || best.After != 4 {
+		t.Fatalf("best = %+v", best)
+	}
+	if len(f.Links["x"]) != 1 {
+		t.Fatalf("farm modified: %v", f.Links)
+	}
+	if _, err := f.WithLink("s", "a"); err == nil {
+		t.Fatal("existing link accepted")
+	}
+}
+
+func TestSuggestLinksCreatesPath(t *testing.T) {
+	// start и end не связаны: тоннель b-e создаёт путь s-a-b-e
+	text := "2\n##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\ns-a\na-b\n"
+	f, err := ParseFarm(context.Background(), strings.NewReader(text), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver, _ := LookupSolver("flow")
+	got, err := SuggestLinks(context.Background(), f, CandidateLinks(f, 1), solver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	best := got[0]
+	if best.From != "b" || best.To != "e" || best.Before != math.MaxInt || best.After != 4 {
+		t.Fatalf("best = %+v", best)
+	}
+	if !best.CreatesPath() {
+		t.Fatal("CreatesPath = false")
+	}
+	for _, s := range got[1:] {
+		if s.CreatesPath() || s.After != math.MaxInt {
+			t.Errorf("unexpected %+v", s)
+		}
+	}
+}
@@ -25,7 +25,7 @@ fi
 
 if [[ "$cmd" == "cli" ]]; then
   echo "Running CLI: $file" >&2
-  go run ./cmd/main.go "$file"
+  go run ./cmd "$file"
 elif [[ "$cmd" == "serve" ]]; then
   echo "Starting server: $file on $addr" >&2
   go run ./cmd/server "$file" "$addr"
@@ -0,0 +1,7 @@
+// Package web — статика визуализатора, встроенная в бинарники.
+package web
+
+import "embed"
+
+//go:embed visual.html script.js style.css image
+var FS embed.FS
@@ -37,6 +37,8 @@ function pickAntImageCandidates() {
   const params = new URLSearchParams(window.location.search);
   const userAnt = params.get('ant') || params.get('antImg');
   const candidates = [];
+  // автономный отчёт (lem-in report) передаёт картинку прямо в странице
+  if (window.LEMIN_ANT_IMAGE) candidates.push(window.LEMIN_ANT_IMAGE);
   if (userAnt) {
     // если пользователь передал относительный путь/имя
     candidates.push(userAnt);
@@ -246,11 +248,15 @@ window.addEventListener('DOMContentLoaded', () => {
   const startBtn2 = document.getElementById('startBtn');
   if (startBtn2) startBtn2.disabled = true;
 
-  fetch(url)
-    .then((res) => {
-      if (!res.ok) throw new Error(`HTTP ${res.status}`);
-      return res.json();
-    })
+  // в автономном отчёте данные уже встроены в страницу
+  const load = window.LEMIN_DATA
+    ? Promise.resolve(window.LEMIN_DATA)
+    : fetch(url).then((res) => {
+        if (!res.ok) throw new Error(`HTTP ${res.status}`);
+        return res.json();
+      });
+
+  load
     .then((json) => {
       data = json;
       startRoom = (data.rooms.find(r => r.isStart) || {}).name || "0";